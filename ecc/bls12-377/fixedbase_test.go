@@ -0,0 +1,308 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12377
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestG1FixedBaseTable(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = 2
+	} else {
+		parameters.MinSuccessfulTests = 10
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// window size, comb spacing
+	configs := [][2]int{{4, 1}, {5, 3}, {2, 7}, {7, 2}}
+	tables := make([]*G1FixedBaseTable, len(configs))
+	for i, cfg := range configs {
+		var err error
+		tables[i], err = NewG1FixedBaseTable(&g1GenAff, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	properties.Property("[G1] Mul should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+			var r big.Int
+			var expected G1Affine
+			s.BigInt(&r)
+			expected.ScalarMultiplication(&g1GenAff, &r)
+			for _, table := range tables {
+				res := table.Mul(&r)
+				if !res.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[G1] BatchMul should output the same result as BatchScalarMultiplication", prop.ForAll(
+		func(mixer fr.Element) bool {
+			var scalars [17]fr.Element
+			for i := 1; i < len(scalars); i++ {
+				scalars[i].SetUint64(uint64(i)).
+					Mul(&scalars[i], &mixer)
+			}
+			// 0 and -1 exercise the corner cases of the digits decomposition
+			scalars[0].SetOne().Neg(&scalars[0])
+			scalars[len(scalars)/2].SetZero()
+
+			expected := BatchScalarMultiplicationG1(&g1GenAff, scalars[:])
+			for _, table := range tables {
+				res := table.BatchMul(scalars[:])
+				for i := range res {
+					if !res[i].Equal(&expected[i]) {
+						return false
+					}
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// serialization round trip
+	for _, table := range tables {
+		var buf bytes.Buffer
+		n, err := table.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Fatal("bytes written don't match the buffer length")
+		}
+		var decoded G1FixedBaseTable
+		if n, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Fatal("bytes read don't match bytes written")
+		}
+		base := decoded.Base()
+		if !base.Equal(&g1GenAff) {
+			t.Fatal("decoded table has a different base")
+		}
+		r := big.NewInt(42)
+		res, expected := decoded.Mul(r), table.Mul(r)
+		if !res.Equal(&expected) {
+			t.Fatal("decoded table doesn't output the same result")
+		}
+
+		// truncated encoding
+		if _, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+			t.Fatal("truncated table should be rejected")
+		}
+
+		// oversized tooth length: c and d are kept, the first tooth claims 2³²-1 points
+		header := append(append([]byte{}, buf.Bytes()[:16]...), 0xff, 0xff, 0xff, 0xff)
+		if _, err = decoded.ReadFrom(bytes.NewReader(header)); err != errInvalidTable {
+			t.Fatal("oversized tooth should be rejected before decoding the points")
+		}
+	}
+
+	// invalid options
+	for _, cfg := range [][2]int{{1, 1}, {17, 1}, {8, 0}, {8, 1000}} {
+		if _, err := NewG1FixedBaseTable(&g1GenAff, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1])); err == nil {
+			t.Fatalf("window size %d and comb spacing %d should be rejected", cfg[0], cfg[1])
+		}
+	}
+}
+
+func BenchmarkG1FixedBaseTable(b *testing.B) {
+	const nbSamples = 1 << 10
+	var scalars [nbSamples]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+
+	for _, cfg := range [][2]int{{4, 1}, {8, 1}, {8, 4}, {12, 1}} {
+		table, _ := NewG1FixedBaseTable(&g1GenAff, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1]))
+		b.Run(fmt.Sprintf("c=%d,d=%d", cfg[0], cfg[1]), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				table.BatchMul(scalars[:])
+			}
+		})
+	}
+
+	b.Run("BatchScalarMultiplication", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			BatchScalarMultiplicationG1(&g1GenAff, scalars[:])
+		}
+	})
+}
+
+func TestG2FixedBaseTable(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = 2
+	} else {
+		parameters.MinSuccessfulTests = 10
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// window size, comb spacing
+	configs := [][2]int{{4, 1}, {5, 3}, {2, 7}, {7, 2}}
+	tables := make([]*G2FixedBaseTable, len(configs))
+	for i, cfg := range configs {
+		var err error
+		tables[i], err = NewG2FixedBaseTable(&g2GenAff, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	properties.Property("[G2] Mul should output the same result as ScalarMultiplication", prop.ForAll(
+		func(s fr.Element) bool {
+			var r big.Int
+			var expected G2Affine
+			s.BigInt(&r)
+			expected.ScalarMultiplication(&g2GenAff, &r)
+			for _, table := range tables {
+				res := table.Mul(&r)
+				if !res.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.Property("[G2] BatchMul should output the same result as BatchScalarMultiplication", prop.ForAll(
+		func(mixer fr.Element) bool {
+			var scalars [17]fr.Element
+			for i := 1; i < len(scalars); i++ {
+				scalars[i].SetUint64(uint64(i)).
+					Mul(&scalars[i], &mixer)
+			}
+			// 0 and -1 exercise the corner cases of the digits decomposition
+			scalars[0].SetOne().Neg(&scalars[0])
+			scalars[len(scalars)/2].SetZero()
+
+			expected := BatchScalarMultiplicationG2(&g2GenAff, scalars[:])
+			for _, table := range tables {
+				res := table.BatchMul(scalars[:])
+				for i := range res {
+					if !res[i].Equal(&expected[i]) {
+						return false
+					}
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// serialization round trip
+	for _, table := range tables {
+		var buf bytes.Buffer
+		n, err := table.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Fatal("bytes written don't match the buffer length")
+		}
+		var decoded G2FixedBaseTable
+		if n, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(buf.Len()) {
+			t.Fatal("bytes read don't match bytes written")
+		}
+		base := decoded.Base()
+		if !base.Equal(&g2GenAff) {
+			t.Fatal("decoded table has a different base")
+		}
+		r := big.NewInt(42)
+		res, expected := decoded.Mul(r), table.Mul(r)
+		if !res.Equal(&expected) {
+			t.Fatal("decoded table doesn't output the same result")
+		}
+
+		// truncated encoding
+		if _, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+			t.Fatal("truncated table should be rejected")
+		}
+
+		// oversized tooth length: c and d are kept, the first tooth claims 2³²-1 points
+		header := append(append([]byte{}, buf.Bytes()[:16]...), 0xff, 0xff, 0xff, 0xff)
+		if _, err = decoded.ReadFrom(bytes.NewReader(header)); err != errInvalidTable {
+			t.Fatal("oversized tooth should be rejected before decoding the points")
+		}
+	}
+
+	// invalid options
+	for _, cfg := range [][2]int{{1, 1}, {17, 1}, {8, 0}, {8, 1000}} {
+		if _, err := NewG2FixedBaseTable(&g2GenAff, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1])); err == nil {
+			t.Fatalf("window size %d and comb spacing %d should be rejected", cfg[0], cfg[1])
+		}
+	}
+}
+
+func BenchmarkG2FixedBaseTable(b *testing.B) {
+	const nbSamples = 1 << 10
+	var scalars [nbSamples]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+
+	for _, cfg := range [][2]int{{4, 1}, {8, 1}, {8, 4}, {12, 1}} {
+		table, _ := NewG2FixedBaseTable(&g2GenAff, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1]))
+		b.Run(fmt.Sprintf("c=%d,d=%d", cfg[0], cfg[1]), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				table.BatchMul(scalars[:])
+			}
+		})
+	}
+
+	b.Run("BatchScalarMultiplication", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			BatchScalarMultiplicationG2(&g2GenAff, scalars[:])
+		}
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// copySRS returns a deep copy of srs
+func copySRS(srs *SRS) *SRS {
+	res := &SRS{G2: srs.G2}
+	res.G1 = append(res.G1, srs.G1...)
+	return res
+}
+
+func TestCeremony(t *testing.T) {
+	const size = 17
+	const nbIterations = 3
+	beacon := []byte("block hash of the beacon")
+
+	initial, err := NewSRS(size, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// successive contributions, ending with the random beacon
+	steps := []*SRS{initial}
+	var proofs []ContributionProof
+	for i := 0; i < 3; i++ {
+		next := copySRS(steps[i])
+		var proof ContributionProof
+		if i < 2 {
+			proof, err = Contribute(next, rand.Reader)
+		} else {
+			proof, err = ContributeBeacon(next, beacon, nbIterations)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps = append(steps, next)
+		proofs = append(proofs, proof)
+	}
+
+	for i := range proofs {
+		if err := VerifyContribution(steps[i], steps[i+1], &proofs[i]); err != nil {
+			t.Fatalf("contribution %d: %v", i, err)
+		}
+	}
+	if err := VerifyBeacon(steps[2], steps[3], &proofs[2], beacon, nbIterations); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckSRS(steps[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	// the final SRS is a valid KZG SRS
+	var p [size]fr.Element
+	for i := range p {
+		p[i].SetRandom()
+	}
+	var point fr.Element
+	point.SetRandom()
+	digest, err := Commit(p[:], steps[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Open(p[:], point, steps[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(&digest, &proof, point, steps[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	// the beacon contribution is deterministic
+	beaconSRS := copySRS(steps[2])
+	beaconProof, err := ContributeBeacon(beaconSRS, beacon, nbIterations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beaconProof != proofs[2] || beaconSRS.G1[size-1] != steps[3].G1[size-1] {
+		t.Fatal("beacon contribution should be deterministic")
+	}
+	if err := VerifyBeacon(steps[2], steps[3], &proofs[2], []byte("another beacon"), nbIterations); !errors.Is(err, ErrInvalidBeacon) {
+		t.Fatalf("expected ErrInvalidBeacon, got %v", err)
+	}
+	if err := VerifyBeacon(steps[0], steps[1], &proofs[0], beacon, nbIterations); !errors.Is(err, ErrInvalidBeacon) {
+		t.Fatalf("expected ErrInvalidBeacon, got %v", err)
+	}
+	for _, n := range []int{-1, 64} {
+		if _, err := ContributeBeacon(copySRS(steps[2]), beacon, n); !errors.Is(err, ErrInvalidNbIterations) {
+			t.Fatalf("%d iterations: expected ErrInvalidNbIterations, got %v", n, err)
+		}
+		if err := VerifyBeacon(steps[2], steps[3], &proofs[2], beacon, n); !errors.Is(err, ErrInvalidNbIterations) {
+			t.Fatalf("%d iterations: expected ErrInvalidNbIterations, got %v", n, err)
+		}
+	}
+
+	// the proof must match the contribution
+	if err := VerifyContribution(steps[1], steps[2], &proofs[0]); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("expected ErrInvalidContribution, got %v", err)
+	}
+	if err := VerifyContribution(steps[0], steps[2], &proofs[1]); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("expected ErrInvalidContribution, got %v", err)
+	}
+	tampered := proofs[1]
+	tampered.Hash[0] ^= 1
+	if err := VerifyContribution(steps[1], steps[2], &tampered); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("expected ErrInvalidContribution, got %v", err)
+	}
+	tampered = proofs[1]
+	tampered.PoK = proofs[0].PoK
+	if err := VerifyContribution(steps[1], steps[2], &tampered); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("expected ErrInvalidContribution, got %v", err)
+	}
+
+	// the updated powers must be consistent
+	tamperedSRS := copySRS(steps[2])
+	tamperedSRS.G1[size-1] = steps[1].G1[size-1]
+	if err := VerifyContribution(steps[1], tamperedSRS, &proofs[1]); !errors.Is(err, ErrInconsistentSRS) {
+		t.Fatalf("expected ErrInconsistentSRS, got %v", err)
+	}
+	tamperedSRS = copySRS(steps[2])
+	tamperedSRS.G1 = tamperedSRS.G1[:size-1]
+	if err := VerifyContribution(steps[1], tamperedSRS, &proofs[1]); !errors.Is(err, ErrInvalidContribution) {
+		t.Fatalf("expected ErrInvalidContribution, got %v", err)
+	}
+}
+
+func TestSerializationContribution(t *testing.T) {
+	prev, err := NewSRS(8, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := copySRS(prev)
+	proof, err := Contribute(next, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := next.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written, err := proof.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var _next SRS
+	var _proof ContributionProof
+	if _, err := _next.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := _proof.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatalf("read %d bytes, wrote %d", read, written)
+	}
+	if _proof != proof {
+		t.Fatal("contribution proof serialization failed")
+	}
+	if err := VerifyContribution(prev, &_next, &_proof); err != nil {
+		t.Fatal(err)
+	}
+}
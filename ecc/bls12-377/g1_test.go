@@ -394,6 +394,24 @@ func TestG1AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS12-377] constant-time scalar multiplication and scalar multiplication should output the same result", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var op1, op2, op3 G1Jac
+			var op4, op5 G1Affine
+			s.ToBigIntRegular(&r)
+			op1.ScalarMultiplication(&g1Gen, &r)
+			op2.ScalarMultiplicationCT(&g1Gen, &r)
+			op3.ScalarMultiplicationCT(&g1Gen, fr.Modulus())
+			op4.ScalarMultiplication(&g1GenAff, &r)
+			op5.ScalarMultiplicationCT(&g1GenAff, &r)
+			return op1.Equal(&op2) && op3.Equal(&g1Infinity) && op4.Equal(&op5)
+
+		},
+		genScalar,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -500,7 +518,6 @@ func BenchmarkG1JacIsInSubGroup(b *testing.B) {
 }
 
 func BenchmarkBatchAddG1Affine(b *testing.B) {
-
 	var P, R pG1AffineC16
 	var RR ppG1AffineC16
 	ridx := make([]int, len(P))
@@ -577,6 +594,14 @@ func BenchmarkG1JacScalarMultiplication(b *testing.B) {
 		}
 	})
 
+	var ct G1Jac
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ct.ScalarMultiplicationCT(&g1Gen, &scalar)
+		}
+	})
+
 }
 
 func BenchmarkG1AffineCofactorClearing(b *testing.B) {
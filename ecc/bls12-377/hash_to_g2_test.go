@@ -18,7 +18,7 @@ package bls12377
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls12-377/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fptower"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 	"math/rand"
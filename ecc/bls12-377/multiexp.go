@@ -22,7 +22,9 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 	"math"
+	"math/bits"
 	"runtime"
+	"sync"
 )
 
 // MultiExp implements section 4 of https://eprint.iacr.org/2012/549.pdf
@@ -80,6 +82,21 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, config ecc.Mul
 		return nil, errors.New("invalid config: config.NbTasks > 1024")
 	}
 
+	// small scalars are processed over fewer windows, without GLV decomposition
+	if config.ScalarsMaxBits != 0 {
+		nbBits := scalarsMaxBits(scalars, config.ScalarsMaxBits, config.NbTasks)
+		if nbBits < fr.Bits {
+			return msmSmallG1(p, nbBits, points, scalars, config), nil
+		}
+		// no need to compute the bound again when splitting
+		config.ScalarsMaxBits = 0
+	}
+
+	// the bucket additions are cheaper on the twisted Edwards form of the curve (see multiexp_edwards.go)
+	if config.TwistedEdwards {
+		return msmEdwardsG1(p, points, scalars, config), nil
+	}
+
 	// here, we compute the best C for nbPoints
 	// we split recursively until nbChunks(c) >= nbTasks,
 	bestC := func(nbPoints int) uint64 {
@@ -134,11 +151,11 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, config ecc.Mul
 }
 
 func _innerMsmG1(p *G1Jac, c uint64, points []G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G1Jac {
-	// partition the scalars
-	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
-
 	nbChunks := computeNbChunks(c)
 
+	// partition the scalars
+	digits, chunkStats := partitionScalars(scalars, c, nbChunks, config.NbTasks)
+
 	// for each chunk, spawn one go routine that'll loop through all the scalars in the
 	// corresponding bit-window
 	// note that buckets is an array allocated on the stack and this is critical for performance
@@ -178,6 +195,133 @@ func _innerMsmG1(p *G1Jac, c uint64, points []G1Affine, scalars []fr.Element, co
 	return msmReduceChunkG1Affine(p, int(c), chChunks[:])
 }
 
+// msmSmallG1 computes the multiExp of scalars of at most nbBits bits. It processes
+// ⌈(nbBits+1)/c⌉ windows, the last one absorbing the carry of the signed digits, skips the
+// windows where all the digits are zero, and only adds the points for 0/1 scalars.
+func msmSmallG1(p *G1Jac, nbBits uint64, points []G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G1Jac {
+	switch nbBits {
+	case 0:
+		return p.Set(&g1Infinity)
+	case 1:
+		return msmBinaryG1(p, points, scalars, config.NbTasks)
+	}
+
+	bestC := func(nbPoints int) uint64 {
+		implementedCs := []uint64{2, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+		C := implementedCs[0]
+		// approximate cost (in group operations)
+		// cost = ⌈(bits+1)/c⌉ * (nbPoints + 2^{c-1})
+		min := math.MaxFloat64
+		for _, c := range implementedCs {
+			if c > nbBits+1 {
+				break
+			}
+			cost := float64(((nbBits + c) / c) * (uint64(nbPoints) + (1 << (c - 1))))
+			if cost < min {
+				min = cost
+				C = c
+			}
+		}
+		return C
+	}
+
+	// there are few windows, so we split the points to use the available tasks
+	nbPoints := len(points)
+	nbSplits := 1
+	if c := bestC(nbPoints); config.NbTasks > int((nbBits+c)/c) {
+		nbSplits = config.NbTasks / int((nbBits+c)/c)
+		if maxSplits := nbPoints / 1024; nbSplits > maxSplits {
+			nbSplits = maxSplits
+		}
+		if nbSplits < 1 {
+			nbSplits = 1
+		}
+	}
+	c := bestC((nbPoints + nbSplits - 1) / nbSplits)
+	nbChunks := (nbBits + c) / c
+
+	if nbSplits == 1 {
+		return _innerMsmSmallG1(p, c, nbChunks, points, scalars, config.NbTasks)
+	}
+	partials := make([]G1Jac, nbSplits)
+	var wg sync.WaitGroup
+	partSize := (nbPoints + nbSplits - 1) / nbSplits
+	for k := 0; k < nbSplits; k++ {
+		start, end := k*partSize, (k+1)*partSize
+		if end > nbPoints {
+			end = nbPoints
+		}
+		if start >= end {
+			partials[k].Set(&g1Infinity)
+			continue
+		}
+		wg.Add(1)
+		go func(k, start, end int) {
+			_innerMsmSmallG1(&partials[k], c, nbChunks, points[start:end], scalars[start:end], 1)
+			wg.Done()
+		}(k, start, end)
+	}
+	wg.Wait()
+	p.Set(&partials[0])
+	for k := 1; k < nbSplits; k++ {
+		p.AddAssign(&partials[k])
+	}
+	return p
+}
+
+func _innerMsmSmallG1(p *G1Jac, c, nbChunks uint64, points []G1Affine, scalars []fr.Element, nbTasks int) *G1Jac {
+	digits, chunkStats := partitionScalars(scalars, c, nbChunks, nbTasks)
+
+	chChunks := make([]chan g1JacExtended, nbChunks)
+	n := len(points)
+	for j := range chChunks {
+		chChunks[j] = make(chan g1JacExtended, 1)
+		chunkDigits := digits[j*n : (j+1)*n]
+
+		// skip the windows where all the digits are zero
+		zero := true
+		for _, digit := range chunkDigits {
+			if digit != 0 {
+				zero = false
+				break
+			}
+		}
+		if zero {
+			var infinity g1JacExtended
+			infinity.setInfinity()
+			chChunks[j] <- infinity
+			continue
+		}
+
+		processChunk := getChunkProcessorG1(c, chunkStats[j])
+		go processChunk(uint64(j), chChunks[j], c, points, chunkDigits)
+	}
+
+	return msmReduceChunkG1Affine(p, int(c), chChunks)
+}
+
+// msmBinaryG1 sets p to the sum of the points whose scalar is one;
+// the other scalars must be zero
+func msmBinaryG1(p *G1Jac, points []G1Affine, scalars []fr.Element, nbTasks int) *G1Jac {
+	var lock sync.Mutex
+	var sum g1JacExtended
+	sum.setInfinity()
+	parallel.Execute(len(points), func(start, end int) {
+		var s g1JacExtended
+		s.setInfinity()
+		for i := start; i < end; i++ {
+			if scalars[i].IsOne() {
+				s.addMixed(&points[i])
+			}
+		}
+		lock.Lock()
+		sum.add(&s)
+		lock.Unlock()
+	}, nbTasks)
+
+	return p.fromJacExtended(&sum)
+}
+
 // getChunkProcessorG1 decides, depending on c window size and statistics for the chunk
 // to return the best algorithm to process the chunk.
 func getChunkProcessorG1(c uint64, stat chunkStat) func(chunkID uint64, chRes chan<- g1JacExtended, c uint64, points []G1Affine, digits []uint16) {
@@ -337,6 +481,16 @@ func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, config ecc.Mul
 		return nil, errors.New("invalid config: config.NbTasks > 1024")
 	}
 
+	// small scalars are processed over fewer windows, without GLV decomposition
+	if config.ScalarsMaxBits != 0 {
+		nbBits := scalarsMaxBits(scalars, config.ScalarsMaxBits, config.NbTasks)
+		if nbBits < fr.Bits {
+			return msmSmallG2(p, nbBits, points, scalars, config), nil
+		}
+		// no need to compute the bound again when splitting
+		config.ScalarsMaxBits = 0
+	}
+
 	// here, we compute the best C for nbPoints
 	// we split recursively until nbChunks(c) >= nbTasks,
 	bestC := func(nbPoints int) uint64 {
@@ -391,11 +545,11 @@ func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, config ecc.Mul
 }
 
 func _innerMsmG2(p *G2Jac, c uint64, points []G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G2Jac {
-	// partition the scalars
-	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
-
 	nbChunks := computeNbChunks(c)
 
+	// partition the scalars
+	digits, chunkStats := partitionScalars(scalars, c, nbChunks, config.NbTasks)
+
 	// for each chunk, spawn one go routine that'll loop through all the scalars in the
 	// corresponding bit-window
 	// note that buckets is an array allocated on the stack and this is critical for performance
@@ -435,6 +589,133 @@ func _innerMsmG2(p *G2Jac, c uint64, points []G2Affine, scalars []fr.Element, co
 	return msmReduceChunkG2Affine(p, int(c), chChunks[:])
 }
 
+// msmSmallG2 computes the multiExp of scalars of at most nbBits bits. It processes
+// ⌈(nbBits+1)/c⌉ windows, the last one absorbing the carry of the signed digits, skips the
+// windows where all the digits are zero, and only adds the points for 0/1 scalars.
+func msmSmallG2(p *G2Jac, nbBits uint64, points []G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G2Jac {
+	switch nbBits {
+	case 0:
+		return p.Set(&g2Infinity)
+	case 1:
+		return msmBinaryG2(p, points, scalars, config.NbTasks)
+	}
+
+	bestC := func(nbPoints int) uint64 {
+		implementedCs := []uint64{2, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+		C := implementedCs[0]
+		// approximate cost (in group operations)
+		// cost = ⌈(bits+1)/c⌉ * (nbPoints + 2^{c-1})
+		min := math.MaxFloat64
+		for _, c := range implementedCs {
+			if c > nbBits+1 {
+				break
+			}
+			cost := float64(((nbBits + c) / c) * (uint64(nbPoints) + (1 << (c - 1))))
+			if cost < min {
+				min = cost
+				C = c
+			}
+		}
+		return C
+	}
+
+	// there are few windows, so we split the points to use the available tasks
+	nbPoints := len(points)
+	nbSplits := 1
+	if c := bestC(nbPoints); config.NbTasks > int((nbBits+c)/c) {
+		nbSplits = config.NbTasks / int((nbBits+c)/c)
+		if maxSplits := nbPoints / 1024; nbSplits > maxSplits {
+			nbSplits = maxSplits
+		}
+		if nbSplits < 1 {
+			nbSplits = 1
+		}
+	}
+	c := bestC((nbPoints + nbSplits - 1) / nbSplits)
+	nbChunks := (nbBits + c) / c
+
+	if nbSplits == 1 {
+		return _innerMsmSmallG2(p, c, nbChunks, points, scalars, config.NbTasks)
+	}
+	partials := make([]G2Jac, nbSplits)
+	var wg sync.WaitGroup
+	partSize := (nbPoints + nbSplits - 1) / nbSplits
+	for k := 0; k < nbSplits; k++ {
+		start, end := k*partSize, (k+1)*partSize
+		if end > nbPoints {
+			end = nbPoints
+		}
+		if start >= end {
+			partials[k].Set(&g2Infinity)
+			continue
+		}
+		wg.Add(1)
+		go func(k, start, end int) {
+			_innerMsmSmallG2(&partials[k], c, nbChunks, points[start:end], scalars[start:end], 1)
+			wg.Done()
+		}(k, start, end)
+	}
+	wg.Wait()
+	p.Set(&partials[0])
+	for k := 1; k < nbSplits; k++ {
+		p.AddAssign(&partials[k])
+	}
+	return p
+}
+
+func _innerMsmSmallG2(p *G2Jac, c, nbChunks uint64, points []G2Affine, scalars []fr.Element, nbTasks int) *G2Jac {
+	digits, chunkStats := partitionScalars(scalars, c, nbChunks, nbTasks)
+
+	chChunks := make([]chan g2JacExtended, nbChunks)
+	n := len(points)
+	for j := range chChunks {
+		chChunks[j] = make(chan g2JacExtended, 1)
+		chunkDigits := digits[j*n : (j+1)*n]
+
+		// skip the windows where all the digits are zero
+		zero := true
+		for _, digit := range chunkDigits {
+			if digit != 0 {
+				zero = false
+				break
+			}
+		}
+		if zero {
+			var infinity g2JacExtended
+			infinity.setInfinity()
+			chChunks[j] <- infinity
+			continue
+		}
+
+		processChunk := getChunkProcessorG2(c, chunkStats[j])
+		go processChunk(uint64(j), chChunks[j], c, points, chunkDigits)
+	}
+
+	return msmReduceChunkG2Affine(p, int(c), chChunks)
+}
+
+// msmBinaryG2 sets p to the sum of the points whose scalar is one;
+// the other scalars must be zero
+func msmBinaryG2(p *G2Jac, points []G2Affine, scalars []fr.Element, nbTasks int) *G2Jac {
+	var lock sync.Mutex
+	var sum g2JacExtended
+	sum.setInfinity()
+	parallel.Execute(len(points), func(start, end int) {
+		var s g2JacExtended
+		s.setInfinity()
+		for i := start; i < end; i++ {
+			if scalars[i].IsOne() {
+				s.addMixed(&points[i])
+			}
+		}
+		lock.Lock()
+		sum.add(&s)
+		lock.Unlock()
+	}, nbTasks)
+
+	return p.fromJacExtended(&sum)
+}
+
 // getChunkProcessorG2 decides, depending on c window size and statistics for the chunk
 // to return the best algorithm to process the chunk.
 func getChunkProcessorG2(c uint64, stat chunkStat) func(chunkID uint64, chRes chan<- g2JacExtended, c uint64, points []G2Affine, digits []uint16) {
@@ -567,6 +848,41 @@ func lastC(c uint64) uint64 {
 	return c + 1 - nbAvailableBits
 }
 
+// scalarsMaxBits returns the bound on the bit length of the scalars set by
+// MultiExpConfig.ScalarsMaxBits, computing it from the scalars if it is negative
+func scalarsMaxBits(scalars []fr.Element, maxBits int, nbTasks int) uint64 {
+	if maxBits > 0 {
+		if maxBits > fr.Bits {
+			return fr.Bits
+		}
+		return uint64(maxBits)
+	}
+
+	var lock sync.Mutex
+	nbBits := 0
+	parallel.Execute(len(scalars), func(start, end int) {
+		m := 0
+		for i := start; i < end; i++ {
+			s := scalars[i].Bits()
+			for j := len(s) - 1; j >= 0; j-- {
+				if s[j] != 0 {
+					if l := 64*j + bits.Len64(s[j]); l > m {
+						m = l
+					}
+					break
+				}
+			}
+		}
+		lock.Lock()
+		if m > nbBits {
+			nbBits = m
+		}
+		lock.Unlock()
+	}, nbTasks)
+
+	return uint64(nbBits)
+}
+
 type chunkStat struct {
 	// relative weight of work compared to other chunks. 100.0 -> nominal weight.
 	weight float32
@@ -581,9 +897,8 @@ type chunkStat struct {
 // 2^{c} to the current digit, making it negative.
 // negative digits can be processed in a later step as adding -G into the bucket instead of G
 // (computing -G is cheap, and this saves us half of the buckets in the MultiExp or BatchScalarMultiplication)
-func partitionScalars(scalars []fr.Element, c uint64, nbTasks int) ([]uint16, []chunkStat) {
-	// number of c-bit radixes in a scalar
-	nbChunks := computeNbChunks(c)
+// nbChunks is the number of c-bit radixes in a scalar; the scalars must fit in nbChunks*c bits.
+func partitionScalars(scalars []fr.Element, c uint64, nbChunks uint64, nbTasks int) ([]uint16, []chunkStat) {
 
 	digits := make([]uint16, len(scalars)*int(nbChunks))
 
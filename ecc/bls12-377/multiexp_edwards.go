@@ -0,0 +1,322 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls12377
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// G1 (y²=x³+1) is birationally equivalent to the twisted Edwards curve
+//
+//	-x²+y² = 1+d⋅x²⋅y²
+//
+// through the Montgomery curve s⋅v² = u³-3s⋅u²+u, with s = 1/√3 and u = s⋅(x+1), v = s⋅y.
+// The map is
+//
+//	(x, y) ↦ (√(-a')⋅(x+1)/y, (u-1)/(u+1))
+//
+// where a' = (2-3s)/s is the coefficient of the Montgomery curve in twisted Edwards
+// form; it is defined on G1 as the exceptional points have order 2 or 4. The point at
+// infinity maps to (0,1). The addition formulas in extended coordinates don't have
+// exceptions on G1 either, as d is a square but the points have odd order.
+var (
+	edSqrt3       fp.Element // √3 = 1/s
+	edInvSqrt3    fp.Element // s = 1/√3
+	edSqrtMinusA  fp.Element // √(-a')
+	edSqrtMinusA3 fp.Element // √(-a')⋅√3
+	edD2          fp.Element // 2⋅d
+)
+
+func init() {
+	edSqrt3.SetString("30567070899668889872121584789658882274245471728719284894883538395508419196346447682510590835309008936731240225793")
+	edInvSqrt3.SetString("10189023633222963290707194929886294091415157242906428298294512798502806398782149227503530278436336312243746741931")
+	edSqrtMinusA.SetString("23560188534917577818843641916571445935985386319233886518929971599490231428764380923487987729215299304184915158756")
+	edSqrtMinusA3.SetString("56283777382779680228884958562124843953495539951195456228503794498253828810785999856344623655942635525555232278523")
+	edD2.SetString("244536567197351118976972678317271058193963773829754279159068307164067353570771581460084726682472071493849921806358")
+}
+
+// g1EdExtended is a point of the twisted Edwards form of G1 in extended coordinates
+// (X:Y:T:Z), with x=X/Z, y=Y/Z and x⋅y=T/Z
+type g1EdExtended struct {
+	X, Y, T, Z fp.Element
+}
+
+// g1EdPrecomputed is an affine point (x,y) of the twisted Edwards form of G1,
+// stored as (y-x, y+x, 2d⋅x⋅y) for the mixed additions
+type g1EdPrecomputed struct {
+	ymx, ypx, t2d fp.Element
+}
+
+// setIdentity sets p to the neutral element (0:1:0:1)
+func (p *g1EdExtended) setIdentity() *g1EdExtended {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.T.SetZero()
+	p.Z.SetOne()
+	return p
+}
+
+// add sets p to p+q
+// https://hyperelliptic.org/EFD/g1p/auto-twisted-extended-1.html#addition-add-2008-hwcd-3
+func (p *g1EdExtended) add(q *g1EdExtended) *g1EdExtended {
+	var A, B, C, D, E, F, G, H, tmp fp.Element
+	A.Sub(&p.Y, &p.X)
+	tmp.Sub(&q.Y, &q.X)
+	A.Mul(&A, &tmp)
+	B.Add(&p.Y, &p.X)
+	tmp.Add(&q.Y, &q.X)
+	B.Mul(&B, &tmp)
+	C.Mul(&p.T, &q.T).Mul(&C, &edD2)
+	D.Mul(&p.Z, &q.Z).Double(&D)
+	E.Sub(&B, &A)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Add(&B, &A)
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+	return p
+}
+
+// addMixed sets p to p+q
+// https://hyperelliptic.org/EFD/g1p/auto-twisted-extended-1.html#addition-madd-2008-hwcd-3
+func (p *g1EdExtended) addMixed(q *g1EdPrecomputed) *g1EdExtended {
+	var A, B, C, D, E, F, G, H fp.Element
+	A.Sub(&p.Y, &p.X).Mul(&A, &q.ymx)
+	B.Add(&p.Y, &p.X).Mul(&B, &q.ypx)
+	C.Mul(&p.T, &q.t2d)
+	D.Double(&p.Z)
+	E.Sub(&B, &A)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Add(&B, &A)
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+	return p
+}
+
+// subMixed sets p to p-q, where -q = (-x, y)
+func (p *g1EdExtended) subMixed(q *g1EdPrecomputed) *g1EdExtended {
+	var A, B, C, D, E, F, G, H fp.Element
+	A.Sub(&p.Y, &p.X).Mul(&A, &q.ypx)
+	B.Add(&p.Y, &p.X).Mul(&B, &q.ymx)
+	C.Mul(&p.T, &q.t2d)
+	D.Double(&p.Z)
+	E.Sub(&B, &A)
+	F.Add(&D, &C)
+	G.Sub(&D, &C)
+	H.Add(&B, &A)
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+	return p
+}
+
+// double sets p to 2q
+// https://hyperelliptic.org/EFD/g1p/auto-twisted-extended-1.html#doubling-dbl-2008-hwcd
+func (p *g1EdExtended) double(q *g1EdExtended) *g1EdExtended {
+	var A, B, C, D, E, F, G, H fp.Element
+	A.Square(&q.X)
+	B.Square(&q.Y)
+	C.Square(&q.Z).Double(&C)
+	D.Neg(&A)
+	E.Add(&q.X, &q.Y).Square(&E).Sub(&E, &A).Sub(&E, &B)
+	G.Add(&D, &B)
+	F.Sub(&G, &C)
+	H.Sub(&D, &B)
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+	return p
+}
+
+// toJacobian maps p back to short Weierstrass form
+func (p *g1EdExtended) toJacobian(res *G1Jac) *G1Jac {
+	if p.X.IsZero() {
+		// p is the neutral element, as G1 has no point of order 2
+		return res.Set(&g1Infinity)
+	}
+
+	// u = (Z+Y)/(Z-Y), x = √3⋅u-1 and y = √3⋅√(-a')⋅u/xₑ = √3⋅√(-a')⋅(Z+Y)⋅Z/((Z-Y)⋅X)
+	var i, zpy, zmy, one fp.Element
+	one.SetOne()
+	zpy.Add(&p.Z, &p.Y)
+	zmy.Sub(&p.Z, &p.Y)
+	i.Mul(&zmy, &p.X).Inverse(&i)
+
+	var a G1Affine
+	a.X.Mul(&zpy, &p.X).Mul(&a.X, &i).Mul(&a.X, &edSqrt3)
+	a.X.Sub(&a.X, &one)
+	a.Y.Mul(&zpy, &p.Z).Mul(&a.Y, &i).Mul(&a.Y, &edSqrtMinusA3)
+
+	return res.FromAffine(&a)
+}
+
+// batchToEdwardsG1 maps the points of G1 to the twisted Edwards form
+func batchToEdwardsG1(points []G1Affine, nbTasks int) []g1EdPrecomputed {
+	n := len(points)
+	var one fp.Element
+	one.SetOne()
+
+	// the map needs 1/y and 1/(u+1)
+	toInvert := make([]fp.Element, 2*n)
+	us := make([]fp.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if points[i].IsInfinity() {
+				continue
+			}
+			toInvert[i] = points[i].Y
+			us[i].Add(&points[i].X, &one).Mul(&us[i], &edInvSqrt3)
+			toInvert[n+i].Add(&us[i], &one)
+		}
+	}, nbTasks)
+	inverses := fp.BatchInvert(toInvert)
+
+	res := make([]g1EdPrecomputed, n)
+	parallel.Execute(n, func(start, end int) {
+		var x, y fp.Element
+		for i := start; i < end; i++ {
+			if points[i].IsInfinity() {
+				res[i].ymx.SetOne()
+				res[i].ypx.SetOne()
+				continue
+			}
+			x.Add(&points[i].X, &one).Mul(&x, &edSqrtMinusA).Mul(&x, &inverses[i])
+			y.Sub(&us[i], &one).Mul(&y, &inverses[n+i])
+			res[i].ymx.Sub(&y, &x)
+			res[i].ypx.Add(&y, &x)
+			res[i].t2d.Mul(&x, &y).Mul(&res[i].t2d, &edD2)
+		}
+	}, nbTasks)
+
+	return res
+}
+
+// msmEdwardsG1 computes the multiExp on the twisted Edwards form of G1, where the
+// bucket additions are cheaper than with the extended Jacobian coordinates.
+// The bases must be in G1.
+func msmEdwardsG1(p *G1Jac, points []G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G1Jac {
+	n := len(points)
+
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	var c uint64
+	min := -1.0
+	for cc := uint64(4); cc <= 16; cc++ {
+		if lastC(cc) > 16 {
+			continue
+		}
+		cost := float64((fr.Bits+1)*(uint64(n)+(1<<cc))) / float64(cc)
+		if min < 0 || cost < min {
+			min = cost
+			c = cc
+		}
+	}
+	nbChunks := int(computeNbChunks(c))
+
+	digits, _ := partitionScalars(scalars, c, uint64(nbChunks), config.NbTasks)
+	edPoints := batchToEdwardsG1(points, config.NbTasks)
+
+	// each chunk is split in parts to use the available tasks
+	nbParts := config.NbTasks / nbChunks
+	if maxParts := n >> c; nbParts > maxParts {
+		nbParts = maxParts
+	}
+	if nbParts < 1 {
+		nbParts = 1
+	}
+	partSize := (n + nbParts - 1) / nbParts
+	partials := make([]g1EdExtended, nbChunks*nbParts)
+	parallel.Execute(len(partials), func(start, end int) {
+		for t := start; t < end; t++ {
+			chunk, part := t/nbParts, t%nbParts
+			from, to := part*partSize, (part+1)*partSize
+			if to > n {
+				to = n
+			}
+			if from >= to {
+				partials[t].setIdentity()
+				continue
+			}
+			// the last digit may need more buckets
+			cc := c
+			if chunk == nbChunks-1 && lastC(c) > c {
+				cc = lastC(c)
+			}
+			processChunkG1Edwards(&partials[t], cc, edPoints[from:to], digits[chunk*n+from:chunk*n+to])
+		}
+	}, config.NbTasks)
+
+	var res g1EdExtended
+	res.setIdentity()
+	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+		if chunk != nbChunks-1 {
+			for i := uint64(0); i < c; i++ {
+				res.double(&res)
+			}
+		}
+		for part := 0; part < nbParts; part++ {
+			res.add(&partials[chunk*nbParts+part])
+		}
+	}
+
+	return res.toJacobian(p)
+}
+
+// processChunkG1Edwards sets res to the weighted sum of the buckets of a chunk of c-bit digits
+func processChunkG1Edwards(res *g1EdExtended, c uint64, points []g1EdPrecomputed, digits []uint16) {
+	buckets := make([]g1EdExtended, 1<<(c-1))
+	isSet := make([]bool, len(buckets))
+	for i, digit := range digits {
+		if digit == 0 {
+			continue
+		}
+		// if msbWindow bit is set, we need to substract
+		b := (digit >> 1) - 1
+		if digit&1 != 0 {
+			b = digit >> 1
+		}
+		if !isSet[b] {
+			isSet[b] = true
+			buckets[b].setIdentity()
+		}
+		if digit&1 == 0 {
+			buckets[b].addMixed(&points[i])
+		} else {
+			buckets[b].subMixed(&points[i])
+		}
+	}
+
+	// reduce buckets into total
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+	var runningSum g1EdExtended
+	runningSum.setIdentity()
+	res.setIdentity()
+	for k := len(buckets) - 1; k >= 0; k-- {
+		if isSet[k] {
+			runningSum.add(&buckets[k])
+		}
+		res.add(&runningSum)
+	}
+}
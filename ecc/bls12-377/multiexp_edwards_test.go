@@ -0,0 +1,163 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls12377
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestTwistedEdwardsG1(t *testing.T) {
+	t.Parallel()
+	const nbSamples = 50
+
+	var s fr.Element
+	var sBig big.Int
+	points := make([]G1Affine, nbSamples)
+	for i := range points {
+		s.SetRandom()
+		points[i].ScalarMultiplication(&g1GenAff, s.BigInt(&sBig))
+	}
+	points[3].X.SetZero()
+	points[3].Y.SetZero()
+
+	// the points are on the twisted Edwards curve and map back to themselves
+	edPoints := batchToEdwardsG1(points, 1)
+	var d, two fp.Element
+	two.SetUint64(2)
+	d.Div(&edD2, &two)
+	for i := range edPoints {
+		var x, y, lhs, rhs, x2, y2 fp.Element
+		x.Sub(&edPoints[i].ypx, &edPoints[i].ymx).Div(&x, &two)
+		y.Add(&edPoints[i].ypx, &edPoints[i].ymx).Div(&y, &two)
+		x2.Square(&x)
+		y2.Square(&y)
+		lhs.Sub(&y2, &x2)
+		rhs.Mul(&x2, &y2).Mul(&rhs, &d).Add(&rhs, new(fp.Element).SetOne())
+		if !lhs.Equal(&rhs) {
+			t.Fatalf("point %d is not on the twisted Edwards curve", i)
+		}
+
+		var e g1EdExtended
+		var res G1Jac
+		var expected G1Jac
+		e.setIdentity().addMixed(&edPoints[i])
+		e.toJacobian(&res)
+		expected.FromAffine(&points[i])
+		if !res.Equal(&expected) {
+			t.Fatalf("point %d doesn't map back to itself", i)
+		}
+	}
+
+	// group law
+	var e1, e2 g1EdExtended
+	var r1, r2, expected G1Jac
+	e1.setIdentity().addMixed(&edPoints[0]).addMixed(&edPoints[1]).subMixed(&edPoints[2])
+	e2.setIdentity().addMixed(&edPoints[0])
+	e2.double(&e2).add(&e1)
+	e1.toJacobian(&r1)
+	e2.toJacobian(&r2)
+	expected.FromAffine(&points[0]).AddMixed(&points[1])
+	var p2 G1Affine
+	p2.Neg(&points[2])
+	expected.AddMixed(&p2)
+	if !r1.Equal(&expected) {
+		t.Fatal("wrong addition on the twisted Edwards curve")
+	}
+	var tmp G1Jac
+	tmp.FromAffine(&points[0]).DoubleAssign()
+	expected.AddAssign(&tmp)
+	if !r2.Equal(&expected) {
+		t.Fatal("wrong doubling on the twisted Edwards curve")
+	}
+}
+
+func TestMultiExpTwistedEdwardsG1(t *testing.T) {
+	t.Parallel()
+	const nbSamples = 1000
+
+	var s fr.Element
+	var sBig big.Int
+	points := make([]G1Affine, nbSamples)
+	scalars := make([]fr.Element, nbSamples)
+	s.SetRandom()
+	points[0].ScalarMultiplication(&g1GenAff, s.BigInt(&sBig))
+	for i := 1; i < nbSamples; i++ {
+		points[i].Add(&points[i-1], &g1GenAff)
+		scalars[i].SetRandom()
+	}
+	points[5].setInfinity()
+	scalars[1].SetOne().Neg(&scalars[1])
+	scalars[2].SetZero()
+
+	var expected G1Jac
+	expected.MultiExp(points, scalars, ecc.MultiExpConfig{})
+
+	for _, nbTasks := range []int{1, 5, 64, 0} {
+		var res G1Jac
+		if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: nbTasks, TwistedEdwards: true}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expected) {
+			t.Fatalf("%d tasks: twisted Edwards multiExp doesn't match", nbTasks)
+		}
+	}
+
+	// the result is the point at infinity
+	var res G1Jac
+	scalars[0].Neg(&scalars[3])
+	points[0] = points[3]
+	res.MultiExp(points[:1], scalars[:1], ecc.MultiExpConfig{TwistedEdwards: true})
+	if res.Z.IsZero() {
+		t.Fatal("multiExp of a single non zero term shouldn't be infinity")
+	}
+	res.MultiExp([]G1Affine{points[3], points[3]}, []fr.Element{scalars[3], scalars[0]}, ecc.MultiExpConfig{TwistedEdwards: true})
+	if !res.Z.IsZero() {
+		t.Fatal("multiExp should be infinity")
+	}
+}
+
+func BenchmarkMultiExpTwistedEdwardsG1(b *testing.B) {
+	const nbSamples = 1 << 16
+	var s fr.Element
+	var sBig big.Int
+	points := make([]G1Affine, nbSamples)
+	scalars := make([]fr.Element, nbSamples)
+	s.SetRandom()
+	points[0].ScalarMultiplication(&g1GenAff, s.BigInt(&sBig))
+	for i := 1; i < nbSamples; i++ {
+		points[i].Add(&points[i-1], &g1GenAff)
+	}
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+
+	for _, using := range []int{1 << 8, 1 << 12, nbSamples} {
+		for _, twistedEdwards := range []bool{false, true} {
+			b.Run(fmt.Sprintf("%d points,TwistedEdwards=%v", using, twistedEdwards), func(b *testing.B) {
+				var res G1Jac
+				b.ResetTimer()
+				for j := 0; j < b.N; j++ {
+					res.MultiExp(points[:using], scalars[:using], ecc.MultiExpConfig{TwistedEdwards: twistedEdwards})
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,435 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12377
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// defaultMultiExpStreamBatchSize is the default number of bases read at once by MultiExpStream
+const defaultMultiExpStreamBatchSize = 1 << 18
+
+var (
+	errNotEnoughBases = errors.New("not enough bases in the stream")
+	errRawBases       = errors.New("invalid raw encoding of the bases")
+)
+
+// MultiExpStream computes ∑ scalars[i] ⋅ bases[i], where the bases are read from dec
+// as a []G1Affine written by an Encoder with RawEncoding, such as the points of
+// an SRS file. Only the first len(scalars) bases are read.
+//
+// The bases are read and decoded in batches of batchSize points (a default size
+// is used if batchSize <= 0), while the previous batch is added to the buckets of
+// the bucket method; the window size is chosen once from len(scalars) and the
+// buckets are reduced once all the batches are processed. The memory footprint
+// is thus a few batches plus the buckets, independently of the number of bases.
+// A memory-mapped file can be streamed through a bytes.Reader, as a batch is
+// copied only once.
+//
+// The bases are checked to be in the subgroup, unless dec was created with NoSubgroupChecks.
+// config.ScalarsMaxBits and config.TwistedEdwards are ignored.
+//
+// This call return an error if the stream holds fewer bases than len(scalars), if
+// a base is not in raw encoding or if provided config is invalid.
+func (p *G1Affine) MultiExpStream(dec *Decoder, scalars []fr.Element, batchSize int, config ecc.MultiExpConfig) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpStream(dec, scalars, batchSize, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpStream computes ∑ scalars[i] ⋅ bases[i], where the bases are read from dec
+// as a []G1Affine written by an Encoder with RawEncoding, such as the points of
+// an SRS file. Only the first len(scalars) bases are read.
+//
+// See G1Affine.MultiExpStream.
+func (p *G1Jac) MultiExpStream(dec *Decoder, scalars []fr.Element, batchSize int, config ecc.MultiExpConfig) (*G1Jac, error) {
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultMultiExpStreamBatchSize
+	}
+
+	nbBases, err := dec.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if int(nbBases) < len(scalars) {
+		return nil, errNotEnoughBases
+	}
+
+	// the next batch is read while the current one is processed
+	type batch struct {
+		points []G1Affine
+		err    error
+	}
+	chBatches := make(chan batch, 1)
+	done := make(chan struct{})
+	defer func() {
+		// the reader must be done with dec before we return
+		close(done)
+		for range chBatches {
+		}
+	}()
+	go func() {
+		defer close(chBatches)
+		buf := make([]byte, batchSize*SizeOfG1AffineUncompressed)
+		for start := 0; start < len(scalars); start += batchSize {
+			n := len(scalars) - start
+			if n > batchSize {
+				n = batchSize
+			}
+			points := make([]G1Affine, n)
+			err := dec.readRawG1Affine(points, buf[:n*SizeOfG1AffineUncompressed])
+			select {
+			case chBatches <- batch{points, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// the buckets of all the windows are kept across the batches
+	c := bestCStreamG1(len(scalars))
+	nbChunks := computeNbChunks(c)
+	buckets := make([][]g1JacExtended, nbChunks)
+	for j := range buckets {
+		nbBuckets := 1 << (c - 1)
+		if j == len(buckets)-1 {
+			nbBuckets = 1 << (lastC(c) - 1)
+		}
+		buckets[j] = make([]g1JacExtended, nbBuckets)
+		for i := range buckets[j] {
+			buckets[j][i].setInfinity()
+		}
+	}
+
+	start := 0
+	for b := range chBatches {
+		if b.err != nil {
+			return nil, b.err
+		}
+		n := len(b.points)
+		digits, _ := partitionScalars(scalars[start:start+n], c, nbChunks, config.NbTasks)
+		parallel.Execute(int(nbChunks), func(startChunk, endChunk int) {
+			for j := startChunk; j < endChunk; j++ {
+				accumulateBucketsG1(buckets[j], b.points, digits[j*n:(j+1)*n])
+			}
+		}, config.NbTasks)
+		start += n
+	}
+
+	// reduce the buckets of each window, then the windows
+	chChunks := make([]chan g1JacExtended, nbChunks)
+	for j := range chChunks {
+		chChunks[j] = make(chan g1JacExtended, 1)
+	}
+	parallel.Execute(int(nbChunks), func(startChunk, endChunk int) {
+		for j := startChunk; j < endChunk; j++ {
+			// total = bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+			var runningSum, total g1JacExtended
+			runningSum.setInfinity()
+			total.setInfinity()
+			for k := len(buckets[j]) - 1; k >= 0; k-- {
+				if !buckets[j][k].ZZ.IsZero() {
+					runningSum.add(&buckets[j][k])
+				}
+				total.add(&runningSum)
+			}
+			chChunks[j] <- total
+		}
+	}, config.NbTasks)
+
+	return msmReduceChunkG1Affine(p, int(c), chChunks), nil
+}
+
+// bestCStreamG1 returns the window size minimizing the cost of the bucket
+// method over nbPoints points, among the sizes used by MultiExp
+func bestCStreamG1(nbPoints int) uint64 {
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	var C uint64
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		cost := float64((fr.Bits+1)*(nbPoints+(1<<c))) / float64(c)
+		if cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
+
+// accumulateBucketsG1 adds the points to the buckets of a window, given
+// their digits in this window computed by partitionScalars
+func accumulateBucketsG1(buckets []g1JacExtended, points []G1Affine, digits []uint16) {
+	for i, digit := range digits {
+		if digit == 0 {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		if digit&1 == 0 {
+			// add
+			buckets[(digit>>1)-1].addMixed(&points[i])
+		} else {
+			// sub
+			buckets[(digit >> 1)].subMixed(&points[i])
+		}
+	}
+}
+
+// readRawG1Affine reads len(points) points in raw encoding; buf must hold
+// SizeOfG1AffineUncompressed bytes per point
+func (dec *Decoder) readRawG1Affine(points []G1Affine, buf []byte) error {
+	read, err := io.ReadFull(dec.r, buf)
+	dec.n += int64(read)
+	if err != nil {
+		return err
+	}
+
+	var nbErrs uint64
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			b := buf[i*SizeOfG1AffineUncompressed : (i+1)*SizeOfG1AffineUncompressed]
+			if n, err := points[i].setBytes(b, dec.subGroupCheck); err != nil || n != len(b) {
+				atomic.AddUint64(&nbErrs, 1)
+			}
+		}
+	})
+	if nbErrs != 0 {
+		return errRawBases
+	}
+
+	return nil
+}
+
+// MultiExpStream computes ∑ scalars[i] ⋅ bases[i], where the bases are read from dec
+// as a []G2Affine written by an Encoder with RawEncoding, such as the points of
+// an SRS file. Only the first len(scalars) bases are read.
+//
+// The bases are read and decoded in batches of batchSize points (a default size
+// is used if batchSize <= 0), while the previous batch is added to the buckets of
+// the bucket method; the window size is chosen once from len(scalars) and the
+// buckets are reduced once all the batches are processed. The memory footprint
+// is thus a few batches plus the buckets, independently of the number of bases.
+// A memory-mapped file can be streamed through a bytes.Reader, as a batch is
+// copied only once.
+//
+// The bases are checked to be in the subgroup, unless dec was created with NoSubgroupChecks.
+// config.ScalarsMaxBits and config.TwistedEdwards are ignored.
+//
+// This call return an error if the stream holds fewer bases than len(scalars), if
+// a base is not in raw encoding or if provided config is invalid.
+func (p *G2Affine) MultiExpStream(dec *Decoder, scalars []fr.Element, batchSize int, config ecc.MultiExpConfig) (*G2Affine, error) {
+	var _p G2Jac
+	if _, err := _p.MultiExpStream(dec, scalars, batchSize, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpStream computes ∑ scalars[i] ⋅ bases[i], where the bases are read from dec
+// as a []G2Affine written by an Encoder with RawEncoding, such as the points of
+// an SRS file. Only the first len(scalars) bases are read.
+//
+// See G2Affine.MultiExpStream.
+func (p *G2Jac) MultiExpStream(dec *Decoder, scalars []fr.Element, batchSize int, config ecc.MultiExpConfig) (*G2Jac, error) {
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultMultiExpStreamBatchSize
+	}
+
+	nbBases, err := dec.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if int(nbBases) < len(scalars) {
+		return nil, errNotEnoughBases
+	}
+
+	// the next batch is read while the current one is processed
+	type batch struct {
+		points []G2Affine
+		err    error
+	}
+	chBatches := make(chan batch, 1)
+	done := make(chan struct{})
+	defer func() {
+		// the reader must be done with dec before we return
+		close(done)
+		for range chBatches {
+		}
+	}()
+	go func() {
+		defer close(chBatches)
+		buf := make([]byte, batchSize*SizeOfG2AffineUncompressed)
+		for start := 0; start < len(scalars); start += batchSize {
+			n := len(scalars) - start
+			if n > batchSize {
+				n = batchSize
+			}
+			points := make([]G2Affine, n)
+			err := dec.readRawG2Affine(points, buf[:n*SizeOfG2AffineUncompressed])
+			select {
+			case chBatches <- batch{points, err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// the buckets of all the windows are kept across the batches
+	c := bestCStreamG2(len(scalars))
+	nbChunks := computeNbChunks(c)
+	buckets := make([][]g2JacExtended, nbChunks)
+	for j := range buckets {
+		nbBuckets := 1 << (c - 1)
+		if j == len(buckets)-1 {
+			nbBuckets = 1 << (lastC(c) - 1)
+		}
+		buckets[j] = make([]g2JacExtended, nbBuckets)
+		for i := range buckets[j] {
+			buckets[j][i].setInfinity()
+		}
+	}
+
+	start := 0
+	for b := range chBatches {
+		if b.err != nil {
+			return nil, b.err
+		}
+		n := len(b.points)
+		digits, _ := partitionScalars(scalars[start:start+n], c, nbChunks, config.NbTasks)
+		parallel.Execute(int(nbChunks), func(startChunk, endChunk int) {
+			for j := startChunk; j < endChunk; j++ {
+				accumulateBucketsG2(buckets[j], b.points, digits[j*n:(j+1)*n])
+			}
+		}, config.NbTasks)
+		start += n
+	}
+
+	// reduce the buckets of each window, then the windows
+	chChunks := make([]chan g2JacExtended, nbChunks)
+	for j := range chChunks {
+		chChunks[j] = make(chan g2JacExtended, 1)
+	}
+	parallel.Execute(int(nbChunks), func(startChunk, endChunk int) {
+		for j := startChunk; j < endChunk; j++ {
+			// total = bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+			var runningSum, total g2JacExtended
+			runningSum.setInfinity()
+			total.setInfinity()
+			for k := len(buckets[j]) - 1; k >= 0; k-- {
+				if !buckets[j][k].ZZ.IsZero() {
+					runningSum.add(&buckets[j][k])
+				}
+				total.add(&runningSum)
+			}
+			chChunks[j] <- total
+		}
+	}, config.NbTasks)
+
+	return msmReduceChunkG2Affine(p, int(c), chChunks), nil
+}
+
+// bestCStreamG2 returns the window size minimizing the cost of the bucket
+// method over nbPoints points, among the sizes used by MultiExp
+func bestCStreamG2(nbPoints int) uint64 {
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	var C uint64
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		cost := float64((fr.Bits+1)*(nbPoints+(1<<c))) / float64(c)
+		if cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
+
+// accumulateBucketsG2 adds the points to the buckets of a window, given
+// their digits in this window computed by partitionScalars
+func accumulateBucketsG2(buckets []g2JacExtended, points []G2Affine, digits []uint16) {
+	for i, digit := range digits {
+		if digit == 0 {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		if digit&1 == 0 {
+			// add
+			buckets[(digit>>1)-1].addMixed(&points[i])
+		} else {
+			// sub
+			buckets[(digit >> 1)].subMixed(&points[i])
+		}
+	}
+}
+
+// readRawG2Affine reads len(points) points in raw encoding; buf must hold
+// SizeOfG2AffineUncompressed bytes per point
+func (dec *Decoder) readRawG2Affine(points []G2Affine, buf []byte) error {
+	read, err := io.ReadFull(dec.r, buf)
+	dec.n += int64(read)
+	if err != nil {
+		return err
+	}
+
+	var nbErrs uint64
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			b := buf[i*SizeOfG2AffineUncompressed : (i+1)*SizeOfG2AffineUncompressed]
+			if n, err := points[i].setBytes(b, dec.subGroupCheck); err != nil || n != len(b) {
+				atomic.AddUint64(&nbErrs, 1)
+			}
+		}
+	})
+	if nbErrs != 0 {
+		return errRawBases
+	}
+
+	return nil
+}
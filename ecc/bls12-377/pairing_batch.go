@@ -0,0 +1,140 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12377
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// BatchPairingCheck checks at once the pairing equations
+//
+//	∏ⱼ e(P[i][j], Q[i][j]) == 1
+//
+// for all i, and returns the indices, in increasing order, of the equations
+// that don't hold. All the equations hold if the returned slice is empty.
+//
+// The equations are folded in a single one with random coefficients ρᵢ
+//
+//	∏ᵢ ∏ⱼ e(ρᵢ⋅P[i][j], Q[i][j]) == 1
+//
+// where the G1 points paired with the same G2 point are summed with a
+// multi-scalar multiplication, so that the check costs one multi-Miller loop
+// over the distinct G2 points and one final exponentiation. When the folded
+// equation doesn't hold, the failing equations are found by bisection. A
+// failing equation goes undetected with probability 1/r.
+//
+// This function doesn't check that the points are in the correct subgroups,
+// which the soundness of the random linear combination relies on.
+func BatchPairingCheck(P [][]G1Affine, Q [][]G2Affine) ([]int, error) {
+	if len(P) != len(Q) {
+		return nil, errors.New("invalid inputs sizes")
+	}
+	indices := make([]int, len(P))
+	for i := range P {
+		if len(P[i]) != len(Q[i]) {
+			return nil, errors.New("invalid inputs sizes")
+		}
+		indices[i] = i
+	}
+	return batchPairingCheck(P, Q, indices, false)
+}
+
+// batchPairingCheck returns the failing equations among the ones in indices.
+// If knownToFail is set, at least one of them is already known not to hold.
+func batchPairingCheck(P [][]G1Affine, Q [][]G2Affine, indices []int, knownToFail bool) ([]int, error) {
+	if len(indices) == 0 {
+		return nil, nil
+	}
+	if !knownToFail {
+		ok, err := foldedPairingCheck(P, Q, indices)
+		if err != nil || ok {
+			return nil, err
+		}
+	}
+	if len(indices) == 1 {
+		return []int{indices[0]}, nil
+	}
+
+	mid := len(indices) / 2
+	left, err := batchPairingCheck(P, Q, indices[:mid], false)
+	if err != nil {
+		return nil, err
+	}
+	// if the left half holds, the failure is in the right half
+	right, err := batchPairingCheck(P, Q, indices[mid:], len(left) == 0)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// foldedPairingCheck checks the random linear combination of the equations in indices
+func foldedPairingCheck(P [][]G1Affine, Q [][]G2Affine, indices []int) (bool, error) {
+	// group the G1 points by the G2 point they are paired with
+	groups := make(map[G2Affine]int)
+	var q []G2Affine
+	var points [][]G1Affine
+	var scalars [][]fr.Element
+
+	var rho fr.Element
+	for n, i := range indices {
+		// the first equation doesn't need a random coefficient
+		if n == 0 {
+			rho.SetOne()
+		} else if _, err := rho.SetRandom(); err != nil {
+			return false, err
+		}
+		for j := range P[i] {
+			if P[i][j].IsInfinity() || Q[i][j].IsInfinity() {
+				continue
+			}
+			g, ok := groups[Q[i][j]]
+			if !ok {
+				g = len(q)
+				groups[Q[i][j]] = g
+				q = append(q, Q[i][j])
+				points = append(points, nil)
+				scalars = append(scalars, nil)
+			}
+			points[g] = append(points[g], P[i][j])
+			scalars[g] = append(scalars[g], rho)
+		}
+	}
+	if len(q) == 0 {
+		return true, nil
+	}
+
+	// fold the G1 sides
+	p := make([]G1Affine, len(q))
+	var s big.Int
+	for g := range q {
+		if len(points[g]) == 1 {
+			scalars[g][0].BigInt(&s)
+			p[g].ScalarMultiplication(&points[g][0], &s)
+			continue
+		}
+		if _, err := p[g].MultiExp(points[g], scalars[g], ecc.MultiExpConfig{}); err != nil {
+			return false, err
+		}
+	}
+
+	return PairingCheck(p, q)
+}
@@ -0,0 +1,492 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12378
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// FixedBaseOption sets an option of a fixed-base precomputation table
+type FixedBaseOption func(*fixedBaseConfig)
+
+type fixedBaseConfig struct {
+	windowSize  uint64
+	combSpacing uint64
+}
+
+const (
+	defaultFixedBaseWindowSize  = 8
+	defaultFixedBaseCombSpacing = 1
+)
+
+var (
+	errInvalidWindowSize  = errors.New("invalid window size")
+	errInvalidCombSpacing = errors.New("invalid comb spacing")
+	errInvalidTable       = errors.New("invalid fixed-base table")
+)
+
+// WithWindowSize sets the number of bits c of the scalar digits.
+// Each tooth of the comb stores 2ᶜ⁻¹ points. Default is 8.
+func WithWindowSize(c int) FixedBaseOption {
+	return func(cfg *fixedBaseConfig) {
+		cfg.windowSize = uint64(c)
+	}
+}
+
+// WithCombSpacing sets the spacing d of the comb, in number of digits.
+// The table stores ⌈⌈fr.Bits/c⌉/d⌉ teeth, and a multiplication costs
+// ⌈fr.Bits/c⌉ mixed additions and (d-1)⋅c doublings.
+// Default is 1, that is one tooth per digit and no doubling.
+func WithCombSpacing(d int) FixedBaseOption {
+	return func(cfg *fixedBaseConfig) {
+		cfg.combSpacing = uint64(d)
+	}
+}
+
+// checkFixedBaseConfig returns an error if the window size or the comb spacing are out of range
+func checkFixedBaseConfig(c, d uint64) error {
+	// partitionScalars needs signed digits to fit in an uint16
+	if c < 2 || c > 16 || lastC(c) > 16 {
+		return errInvalidWindowSize
+	}
+	if d < 1 || d > computeNbChunks(c) {
+		return errInvalidCombSpacing
+	}
+	return nil
+}
+
+// G1FixedBaseTable holds precomputed multiples of a fixed base in G1Affine,
+// to speed up repeated scalar multiplications by this base.
+//
+// Scalars are split in signed c-bit digits (see partitionScalars). The digits are
+// arranged in a comb: tooth j stores the multiples 1..2ᶜ⁻¹ of 2^(c⋅d⋅j) ⋅ base,
+// where d is the comb spacing, such that a scalar multiplication only costs table
+// lookups, mixed additions and (d-1)⋅c doublings.
+type G1FixedBaseTable struct {
+	c, d  uint64
+	teeth [][]G1Affine
+}
+
+// NewG1FixedBaseTable precomputes the table of the given base.
+// The precomputation costs about 2ᶜ⁻¹⋅⌈fr.Bits/(c⋅d)⌉ additions, and should be done
+// once per process for each fixed base.
+func NewG1FixedBaseTable(base *G1Affine, opts ...FixedBaseOption) (*G1FixedBaseTable, error) {
+	cfg := fixedBaseConfig{
+		windowSize:  defaultFixedBaseWindowSize,
+		combSpacing: defaultFixedBaseCombSpacing,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c, d := cfg.windowSize, cfg.combSpacing
+	if err := checkFixedBaseConfig(c, d); err != nil {
+		return nil, err
+	}
+
+	t := &G1FixedBaseTable{c: c, d: d}
+	sizes := t.toothSizes()
+
+	// compute all the multiples in Jacobian coordinates, then batch convert them
+	nbPoints := 0
+	for _, s := range sizes {
+		nbPoints += s
+	}
+	points := make([]G1Jac, nbPoints)
+	var toothBase G1Jac
+	toothBase.FromAffine(base)
+	offset := 0
+	for j, s := range sizes {
+		tooth := points[offset : offset+s]
+		tooth[0].Set(&toothBase)
+		for i := 1; i < s; i++ {
+			tooth[i].Set(&tooth[i-1]).AddAssign(&toothBase)
+		}
+		offset += s
+		if j != len(sizes)-1 {
+			for i := uint64(0); i < c*d; i++ {
+				toothBase.DoubleAssign()
+			}
+		}
+	}
+	pointsAff := BatchJacobianToAffineG1(points)
+
+	t.teeth = make([][]G1Affine, len(sizes))
+	offset = 0
+	for j, s := range sizes {
+		t.teeth[j] = pointsAff[offset : offset+s : offset+s]
+		offset += s
+	}
+
+	return t, nil
+}
+
+// toothSizes returns the number of points stored in each tooth of the comb;
+// the last one may be larger as the last digit may be larger (see lastC)
+func (t *G1FixedBaseTable) toothSizes() []int {
+	nbChunks := computeNbChunks(t.c)
+	nbTeeth := (nbChunks + t.d - 1) / t.d
+	sizes := make([]int, nbTeeth)
+	for j := range sizes {
+		sizes[j] = 1 << (t.c - 1)
+	}
+	if maxC := lastC(t.c); maxC > t.c {
+		sizes[nbTeeth-1] = 1 << (maxC - 1)
+	}
+	return sizes
+}
+
+// Base returns the base of the table
+func (t *G1FixedBaseTable) Base() G1Affine {
+	return t.teeth[0][0]
+}
+
+// Mul returns s ⋅ base, where s is reduced modulo r
+func (t *G1FixedBaseTable) Mul(s *big.Int) G1Affine {
+	var e fr.Element
+	e.SetBigInt(s)
+	digits, _ := partitionScalars([]fr.Element{e}, t.c, computeNbChunks(t.c), 1)
+
+	var p G1Jac
+	t.mul(&p, digits, 1)
+
+	var res G1Affine
+	res.FromJacobian(&p)
+	return res
+}
+
+// BatchMul returns scalars[i] ⋅ base for all i, in affine coordinates
+func (t *G1FixedBaseTable) BatchMul(scalars []fr.Element) []G1Affine {
+	digits, _ := partitionScalars(scalars, t.c, computeNbChunks(t.c), runtime.NumCPU())
+
+	res := make([]G1Jac, len(scalars))
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			t.mul(&res[i], digits[i:], len(scalars))
+		}
+	})
+
+	return BatchJacobianToAffineG1(res)
+}
+
+// mul sets p to the multiple of the base given by the digits of a scalar
+// computed by partitionScalars; the k-th digit is digits[k*stride]
+func (t *G1FixedBaseTable) mul(p *G1Jac, digits []uint16, stride int) *G1Jac {
+	nbChunks := int(computeNbChunks(t.c))
+	d := int(t.d)
+
+	p.Set(&g1Infinity)
+	for i := d - 1; i >= 0; i-- {
+		if i != d-1 {
+			for j := uint64(0); j < t.c; j++ {
+				p.DoubleAssign()
+			}
+		}
+		for j := range t.teeth {
+			chunk := j*d + i
+			if chunk >= nbChunks {
+				break
+			}
+			digit := digits[chunk*stride]
+			if digit == 0 {
+				continue
+			}
+
+			// if msbWindow bit is set, we need to substract
+			if digit&1 == 0 {
+				// add
+				p.AddMixed(&t.teeth[j][(digit>>1)-1])
+			} else {
+				// sub
+				q := t.teeth[j][digit>>1]
+				q.Neg(&q)
+				p.AddMixed(&q)
+			}
+		}
+	}
+
+	return p
+}
+
+// WriteTo writes the raw binary encoding of the table
+func (t *G1FixedBaseTable) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w, RawEncoding())
+
+	if err := enc.Encode(t.c); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(t.d); err != nil {
+		return enc.BytesWritten(), err
+	}
+	for j := range t.teeth {
+		if err := enc.Encode(t.teeth[j]); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a table written by WriteTo.
+// The points are checked to be on the curve and in the subgroup, but not to
+// be the multiples of the same base.
+func (t *G1FixedBaseTable) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+
+	if err := dec.Decode(&t.c); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := dec.Decode(&t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := checkFixedBaseConfig(t.c, t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+
+	sizes := t.toothSizes()
+	t.teeth = make([][]G1Affine, len(sizes))
+	for j, s := range sizes {
+		// the length prefix is checked before allocating the tooth, so that a
+		// malformed header can't trigger an arbitrarily large allocation
+		n, err := dec.readUint32()
+		if err != nil {
+			return dec.BytesRead(), err
+		}
+		if int(n) != s {
+			return dec.BytesRead(), errInvalidTable
+		}
+		t.teeth[j] = make([]G1Affine, s)
+		if err := dec.readG1AffineSlice(t.teeth[j]); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// G2FixedBaseTable holds precomputed multiples of a fixed base in G2Affine,
+// to speed up repeated scalar multiplications by this base.
+//
+// Scalars are split in signed c-bit digits (see partitionScalars). The digits are
+// arranged in a comb: tooth j stores the multiples 1..2ᶜ⁻¹ of 2^(c⋅d⋅j) ⋅ base,
+// where d is the comb spacing, such that a scalar multiplication only costs table
+// lookups, mixed additions and (d-1)⋅c doublings.
+type G2FixedBaseTable struct {
+	c, d  uint64
+	teeth [][]G2Affine
+}
+
+// NewG2FixedBaseTable precomputes the table of the given base.
+// The precomputation costs about 2ᶜ⁻¹⋅⌈fr.Bits/(c⋅d)⌉ additions, and should be done
+// once per process for each fixed base.
+func NewG2FixedBaseTable(base *G2Affine, opts ...FixedBaseOption) (*G2FixedBaseTable, error) {
+	cfg := fixedBaseConfig{
+		windowSize:  defaultFixedBaseWindowSize,
+		combSpacing: defaultFixedBaseCombSpacing,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c, d := cfg.windowSize, cfg.combSpacing
+	if err := checkFixedBaseConfig(c, d); err != nil {
+		return nil, err
+	}
+
+	t := &G2FixedBaseTable{c: c, d: d}
+	sizes := t.toothSizes()
+
+	// compute all the multiples in Jacobian coordinates, then batch convert them
+	nbPoints := 0
+	for _, s := range sizes {
+		nbPoints += s
+	}
+	points := make([]G2Jac, nbPoints)
+	var toothBase G2Jac
+	toothBase.FromAffine(base)
+	offset := 0
+	for j, s := range sizes {
+		tooth := points[offset : offset+s]
+		tooth[0].Set(&toothBase)
+		for i := 1; i < s; i++ {
+			tooth[i].Set(&tooth[i-1]).AddAssign(&toothBase)
+		}
+		offset += s
+		if j != len(sizes)-1 {
+			for i := uint64(0); i < c*d; i++ {
+				toothBase.DoubleAssign()
+			}
+		}
+	}
+	pointsAff := BatchJacobianToAffineG2(points)
+
+	t.teeth = make([][]G2Affine, len(sizes))
+	offset = 0
+	for j, s := range sizes {
+		t.teeth[j] = pointsAff[offset : offset+s : offset+s]
+		offset += s
+	}
+
+	return t, nil
+}
+
+// toothSizes returns the number of points stored in each tooth of the comb;
+// the last one may be larger as the last digit may be larger (see lastC)
+func (t *G2FixedBaseTable) toothSizes() []int {
+	nbChunks := computeNbChunks(t.c)
+	nbTeeth := (nbChunks + t.d - 1) / t.d
+	sizes := make([]int, nbTeeth)
+	for j := range sizes {
+		sizes[j] = 1 << (t.c - 1)
+	}
+	if maxC := lastC(t.c); maxC > t.c {
+		sizes[nbTeeth-1] = 1 << (maxC - 1)
+	}
+	return sizes
+}
+
+// Base returns the base of the table
+func (t *G2FixedBaseTable) Base() G2Affine {
+	return t.teeth[0][0]
+}
+
+// Mul returns s ⋅ base, where s is reduced modulo r
+func (t *G2FixedBaseTable) Mul(s *big.Int) G2Affine {
+	var e fr.Element
+	e.SetBigInt(s)
+	digits, _ := partitionScalars([]fr.Element{e}, t.c, computeNbChunks(t.c), 1)
+
+	var p G2Jac
+	t.mul(&p, digits, 1)
+
+	var res G2Affine
+	res.FromJacobian(&p)
+	return res
+}
+
+// BatchMul returns scalars[i] ⋅ base for all i, in affine coordinates
+func (t *G2FixedBaseTable) BatchMul(scalars []fr.Element) []G2Affine {
+	digits, _ := partitionScalars(scalars, t.c, computeNbChunks(t.c), runtime.NumCPU())
+
+	res := make([]G2Jac, len(scalars))
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			t.mul(&res[i], digits[i:], len(scalars))
+		}
+	})
+
+	return BatchJacobianToAffineG2(res)
+}
+
+// mul sets p to the multiple of the base given by the digits of a scalar
+// computed by partitionScalars; the k-th digit is digits[k*stride]
+func (t *G2FixedBaseTable) mul(p *G2Jac, digits []uint16, stride int) *G2Jac {
+	nbChunks := int(computeNbChunks(t.c))
+	d := int(t.d)
+
+	p.Set(&g2Infinity)
+	for i := d - 1; i >= 0; i-- {
+		if i != d-1 {
+			for j := uint64(0); j < t.c; j++ {
+				p.DoubleAssign()
+			}
+		}
+		for j := range t.teeth {
+			chunk := j*d + i
+			if chunk >= nbChunks {
+				break
+			}
+			digit := digits[chunk*stride]
+			if digit == 0 {
+				continue
+			}
+
+			// if msbWindow bit is set, we need to substract
+			if digit&1 == 0 {
+				// add
+				p.AddMixed(&t.teeth[j][(digit>>1)-1])
+			} else {
+				// sub
+				q := t.teeth[j][digit>>1]
+				q.Neg(&q)
+				p.AddMixed(&q)
+			}
+		}
+	}
+
+	return p
+}
+
+// WriteTo writes the raw binary encoding of the table
+func (t *G2FixedBaseTable) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w, RawEncoding())
+
+	if err := enc.Encode(t.c); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(t.d); err != nil {
+		return enc.BytesWritten(), err
+	}
+	for j := range t.teeth {
+		if err := enc.Encode(t.teeth[j]); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a table written by WriteTo.
+// The points are checked to be on the curve and in the subgroup, but not to
+// be the multiples of the same base.
+func (t *G2FixedBaseTable) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+
+	if err := dec.Decode(&t.c); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := dec.Decode(&t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := checkFixedBaseConfig(t.c, t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+
+	sizes := t.toothSizes()
+	t.teeth = make([][]G2Affine, len(sizes))
+	for j, s := range sizes {
+		// the length prefix is checked before allocating the tooth, so that a
+		// malformed header can't trigger an arbitrarily large allocation
+		n, err := dec.readUint32()
+		if err != nil {
+			return dec.BytesRead(), err
+		}
+		if int(n) != s {
+			return dec.BytesRead(), errInvalidTable
+		}
+		t.teeth[j] = make([]G2Affine, s)
+		if err := dec.readG2AffineSlice(t.teeth[j]); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fptower provides the extension field tower of bls12-378,
+// E2 = Fp[u]/(u²+5), E6 = E2[v]/(v³-u) and E12 = E6[w]/(w²-v).
+//
+// E12 is the field of the target group of the pairing: bls12-378.GT is an alias of
+// E12. Besides the field arithmetic, E12 implements the operations used by the
+// pairing and by protocols verifying it, such as the Frobenius maps, the
+// exponentiation by the seed of the curve (Expt) and the compressed
+// representations of the cyclotomic subgroup.
+package fptower
@@ -0,0 +1,210 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fptower
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// MultiExp sets z = ∏ᵢ xᵢ^kᵢ (mod q¹²) and returns it.
+// The xᵢ must be in GT.
+//
+// Each exponent is split in two halves with the GLV decomposition of ExpGLV, the
+// Frobenius map acting on GT as an exponentiation. The 2⋅len(x) exponentiations
+// are then computed with the bucket method on signed c-bit windows, which needs
+// only multiplications and cyclotomic squarings. The windows, and if needed
+// parts of the inputs, are processed in parallel by config.NbTasks go routines.
+func (z *E12) MultiExp(x []E12, k []fr.Element, config ecc.MultiExpConfig) (*E12, error) {
+	n := len(x)
+	if n != len(k) {
+		return nil, errors.New("len(x) != len(k)")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	// GLV decomposition: xᵢ^kᵢ = (±xᵢ)^|sᵢ₀| ⋅ (±π(xᵢ))^|sᵢ₁|,
+	// where ±y denotes y or its inverse y̅
+	m := 2 * n
+	bases := make([]E12, m)
+	scalars := make([][fr.Limbs]uint64, m)
+	parallel.Execute(n, func(start, end int) {
+		var e big.Int
+		var s fr.Element
+		for i := start; i < end; i++ {
+			k[i].BigInt(&e)
+			split := ecc.SplitScalar(&e, &glvBasis)
+			bases[2*i].Set(&x[i])
+			bases[2*i+1].Frobenius(&x[i])
+			for j := 0; j < 2; j++ {
+				if split[j].Sign() == -1 {
+					split[j].Neg(&split[j])
+					bases[2*i+j].Conjugate(&bases[2*i+j])
+				}
+				// |sᵢⱼ| < r, so that this doesn't reduce it
+				scalars[2*i+j] = s.SetBigInt(&split[j]).Bits()
+			}
+		}
+	}, config.NbTasks)
+
+	nbBits := 0
+	for i := range scalars {
+		for j := fr.Limbs - 1; j >= 0; j-- {
+			if scalars[i][j] != 0 {
+				if l := 64*j + bits.Len64(scalars[i][j]); l > nbBits {
+					nbBits = l
+				}
+				break
+			}
+		}
+	}
+	if nbBits == 0 {
+		return z.SetOne(), nil
+	}
+
+	// approximate cost in multiplications: nbWindows ⋅ (m + 2ᶜ)
+	c, minCost := 0, math.MaxFloat64
+	for _c := 2; _c <= 13; _c++ {
+		cost := float64((nbBits+_c)/_c) * float64(m+(1<<_c))
+		if cost < minCost {
+			c, minCost = _c, cost
+		}
+	}
+
+	// signed digits in [-2ᶜ⁻¹, 2ᶜ⁻¹]; one more bit than nbBits to absorb the last carry
+	nbWindows := (nbBits + c) / c
+	digits := make([]int32, nbWindows*m)
+	parallel.Execute(m, func(start, end int) {
+		for i := start; i < end; i++ {
+			carry := 0
+			for w := 0; w < nbWindows; w++ {
+				d := extractBits(&scalars[i], w*c, c) + carry
+				carry = 0
+				if w != nbWindows-1 && d >= 1<<(c-1) {
+					d -= 1 << c
+					carry = 1
+				}
+				digits[w*m+i] = int32(d)
+			}
+		}
+	}, config.NbTasks)
+
+	// if there are less windows than tasks, we also split the inputs
+	nbSplits := 1
+	if config.NbTasks > nbWindows {
+		nbSplits = config.NbTasks / nbWindows
+		if maxSplits := m>>c + 1; nbSplits > maxSplits {
+			nbSplits = maxSplits
+		}
+	}
+	splitSize := (m + nbSplits - 1) / nbSplits
+
+	results := make([]E12, nbWindows*nbSplits)
+	parallel.Execute(len(results), func(start, end int) {
+		buckets := make([]E12, 1<<(c-1))
+		isSet := make([]bool, len(buckets))
+		for t := start; t < end; t++ {
+			w, split := t/nbSplits, t%nbSplits
+			from, to := split*splitSize, (split+1)*splitSize
+			if to > m {
+				to = m
+			}
+			multiExpWindowE12(&results[t], buckets, isSet, bases[from:to], digits[w*m+from:w*m+to])
+		}
+	}, config.NbTasks)
+
+	// combine the windows: res = ∏_w results[w]^(2^(c⋅w))
+	var res E12
+	res.SetOne()
+	for w := nbWindows - 1; w >= 0; w-- {
+		if w != nbWindows-1 {
+			for j := 0; j < c; j++ {
+				res.CyclotomicSquare(&res)
+			}
+		}
+		for split := 0; split < nbSplits; split++ {
+			res.Mul(&res, &results[w*nbSplits+split])
+		}
+	}
+
+	z.Set(&res)
+	return z, nil
+}
+
+// multiExpWindowE12 sets res = ∏ᵢ xᵢ^dᵢ for the signed digits dᵢ of a window,
+// with the bucket method: bucket j collects the xᵢ (or their inverse) with |dᵢ| = j+1.
+func multiExpWindowE12(res *E12, buckets []E12, isSet []bool, x []E12, digits []int32) {
+	for j := range isSet {
+		isSet[j] = false
+	}
+
+	var tmp E12
+	for i, d := range digits {
+		if d == 0 {
+			continue
+		}
+		b := &x[i]
+		if d < 0 {
+			// x̅ = x⁻¹ for x in GT
+			tmp.Conjugate(b)
+			b = &tmp
+			d = -d
+		}
+		if isSet[d-1] {
+			buckets[d-1].Mul(&buckets[d-1], b)
+		} else {
+			buckets[d-1].Set(b)
+			isSet[d-1] = true
+		}
+	}
+
+	// ∏ⱼ bucketⱼ^(j+1) = ∏ⱼ ∏_{l≥j} bucketₗ
+	var acc E12
+	acc.SetOne()
+	res.SetOne()
+	for j := len(buckets) - 1; j >= 0; j-- {
+		if isSet[j] {
+			acc.Mul(&acc, &buckets[j])
+		}
+		res.Mul(res, &acc)
+	}
+}
+
+// extractBits returns the c bits of s starting at bit offset o
+func extractBits(s *[fr.Limbs]uint64, o, c int) int {
+	l, shift := o/64, uint(o%64)
+	if l >= fr.Limbs {
+		return 0
+	}
+	v := s[l] >> shift
+	if int(shift)+c > 64 && l+1 < fr.Limbs {
+		v |= s[l+1] << (64 - shift)
+	}
+	return int(v & (1<<uint(c) - 1))
+}
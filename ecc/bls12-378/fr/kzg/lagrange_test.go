@@ -0,0 +1,137 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/fft"
+)
+
+func TestLagrange(t *testing.T) {
+	const size = 32
+	domain := fft.NewDomain(size)
+	srs, err := NewSRSLagrange(testSRS, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// [Lᵢ(τ)]G₁ is the commitment to Lᵢ
+	l := make([]fr.Element, size)
+	l[5].SetOne()
+	domain.FFTInverse(l, fft.DIF)
+	fft.BitReverse(l)
+	expected, err := Commit(l, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expected.Equal(&srs.G1[5]) {
+		t.Fatal("wrong lagrange srs")
+	}
+
+	// a random polynomial in canonical and Lagrange form
+	p := make([]fr.Element, size)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	evaluations := make([]fr.Element, size)
+	copy(evaluations, p)
+	domain.FFT(evaluations, fft.DIF)
+	fft.BitReverse(evaluations)
+
+	digest, err := Commit(p, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digestLagrange, err := CommitLagrange(evaluations, srs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !digest.Equal(&digestLagrange) {
+		t.Fatal("commitments in canonical and lagrange form differ")
+	}
+
+	// openings outside and inside the domain
+	var point fr.Element
+	point.SetRandom()
+	var omega3 fr.Element
+	omega3.Exp(domain.Generator, big.NewInt(3))
+	for _, z := range []fr.Element{point, fr.One(), omega3} {
+		proof, err := OpenLagrange(evaluations, z, domain, srs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := Open(p, z, testSRS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expected {
+			t.Fatal("opening proofs in canonical and lagrange form differ")
+		}
+		if err := Verify(&digestLagrange, &proof, z, testSRS); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// sizes
+	if _, err := CommitLagrange(evaluations[:size-1], srs); err != ErrInvalidDomainSize {
+		t.Fatalf("expected ErrInvalidDomainSize, got %v", err)
+	}
+	if _, err := OpenLagrange(evaluations, point, fft.NewDomain(2*size), srs); err != ErrInvalidDomainSize {
+		t.Fatalf("expected ErrInvalidDomainSize, got %v", err)
+	}
+	if _, err := NewSRSLagrange(testSRS, fft.NewDomain(uint64(2*len(testSRS.G1)))); err != ErrInvalidDomainSize {
+		t.Fatalf("expected ErrInvalidDomainSize, got %v", err)
+	}
+}
+
+func TestSerializationSRSLagrange(t *testing.T) {
+	srs, err := NewSRSLagrange(testSRS, fft.NewDomain(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := srs.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _srs SRSLagrange
+	read, err := _srs.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatalf("read %d bytes, wrote %d", read, written)
+	}
+	if !reflect.DeepEqual(srs, &_srs) {
+		t.Fatal("lagrange srs serialization failed")
+	}
+}
+
+func BenchmarkToLagrangeG1(b *testing.B) {
+	const size = 1 << 8
+	domain := fft.NewDomain(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ToLagrangeG1(testSRS.G1[:size], domain)
+	}
+}
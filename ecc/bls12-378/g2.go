@@ -17,9 +17,10 @@
 package bls12378
 
 import (
+	"crypto/subtle"
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fptower"
 	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
-	"github.com/consensys/gnark-crypto/ecc/bls12-378/internal/fptower"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 	"math/big"
 	"runtime"
@@ -45,6 +46,12 @@ type g2Proj struct {
 	x, y, z fptower.E2
 }
 
+// g2ProjCT point in homogeneous projective coordinates (x=X/Z, y=Y/Z),
+// used with complete addition formulas for constant-time scalar multiplication
+type g2ProjCT struct {
+	X, Y, Z fptower.E2
+}
+
 // -------------------------------------------------------------------------------------------------
 // Affine
 
@@ -70,6 +77,16 @@ func (p *G2Affine) ScalarMultiplication(a *G2Affine, s *big.Int) *G2Affine {
 	return p
 }
 
+// ScalarMultiplicationCT computes and returns p = a ⋅ s in constant time with respect to s.
+// see G2Jac.ScalarMultiplicationCT
+func (p *G2Affine) ScalarMultiplicationCT(a *G2Affine, s *big.Int) *G2Affine {
+	var _p G2Jac
+	_p.FromAffine(a)
+	_p.ScalarMultiplicationCT(&_p, s)
+	p.FromJacobian(&_p)
+	return p
+}
+
 // Add adds two point in affine coordinates.
 // This should rarely be used as it is very inefficient compared to Jacobian
 func (p *G2Affine) Add(a, b *G2Affine) *G2Affine {
@@ -340,6 +357,45 @@ func (p *G2Jac) ScalarMultiplication(a *G2Jac, s *big.Int) *G2Jac {
 	return p.mulGLV(a, s)
 }
 
+// ScalarMultiplicationCT computes and returns p = a ⋅ s in constant time with respect to s.
+// It should be used instead of ScalarMultiplication when s is secret.
+//
+// The scalar is reduced modulo r, so a is expected to be in the r-torsion.
+// It uses a fixed 4-bits window, table lookups that read all the entries, and
+// the complete formulas of https://eprint.iacr.org/2015/1060.pdf.
+func (p *G2Jac) ScalarMultiplicationCT(a *G2Jac, s *big.Int) *G2Jac {
+	const (
+		windowSize = 4
+		tableSize  = 1 << windowSize
+	)
+
+	var e fr.Element
+	e.SetBigInt(s)
+	b := e.Bytes()
+
+	// table[i] = i ⋅ a
+	var table [tableSize]g2ProjCT
+	table[0].setInfinity()
+	table[1].fromJacobian(a)
+	for i := 2; i < tableSize; i++ {
+		table[i].add(&table[i-1], &table[1])
+	}
+
+	var res, t g2ProjCT
+	res.setInfinity()
+	for i := range b {
+		for _, w := range [2]uint8{b[i] >> windowSize, b[i] & (tableSize - 1)} {
+			for j := 0; j < windowSize; j++ {
+				res.double(&res)
+			}
+			t.lookup(&table, w)
+			res.add(&res, &t)
+		}
+	}
+
+	return p.fromProjCT(&res)
+}
+
 // String returns canonical representation of the point in affine coordinates
 func (p *G2Jac) String() string {
 	_p := G2Affine{}
@@ -843,6 +899,126 @@ func (p *g2JacExtended) doubleMixed(q *G2Affine) *g2JacExtended {
 	return p
 }
 
+// -------------------------------------------------------------------------------------------------
+// Homogenous projective, complete formulas
+
+// setInfinity sets p to O (0:1:0)
+func (p *g2ProjCT) setInfinity() *g2ProjCT {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.Z.SetZero()
+	return p
+}
+
+// fromJacobian sets p = q, p in homogenous projective, q in Jacobian
+func (p *g2ProjCT) fromJacobian(q *G2Jac) *g2ProjCT {
+	// (X, Y, Z) → (X⋅Z, Y, Z³)
+	var ZZ fptower.E2
+	ZZ.Square(&q.Z)
+	p.X.Mul(&q.X, &q.Z)
+	p.Y.Set(&q.Y)
+	p.Z.Mul(&ZZ, &q.Z)
+	return p
+}
+
+// fromProjCT sets p = q, p in Jacobian, q in homogenous projective
+func (p *G2Jac) fromProjCT(q *g2ProjCT) *G2Jac {
+	if q.Z.IsZero() {
+		return p.Set(&g2Infinity)
+	}
+	// (X, Y, Z) → (X⋅Z, Y⋅Z², Z)
+	var ZZ fptower.E2
+	ZZ.Square(&q.Z)
+	p.X.Mul(&q.X, &q.Z)
+	p.Y.Mul(&q.Y, &ZZ)
+	p.Z.Set(&q.Z)
+	return p
+}
+
+// lookup sets p = table[w] in constant time, reading all the entries of the table
+func (p *g2ProjCT) lookup(table *[16]g2ProjCT, w uint8) *g2ProjCT {
+	for i := range table {
+		c := subtle.ConstantTimeByteEq(w, uint8(i))
+		p.X.Select(c, &p.X, &table[i].X)
+		p.Y.Select(c, &p.Y, &table[i].Y)
+		p.Z.Select(c, &p.Z, &table[i].Z)
+	}
+	return p
+}
+
+// add sets p = a + b and returns p
+// complete addition formula for short Weierstrass curves with a=0, on groups of odd order
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 7)
+func (p *g2ProjCT) add(a, b *g2ProjCT) *g2ProjCT {
+	var b3, t0, t1, t2, t3, t4, X3, Y3, Z3 fptower.E2
+	b3.Double(&bTwistCurveCoeff).Add(&b3, &bTwistCurveCoeff)
+
+	t0.Mul(&a.X, &b.X)
+	t1.Mul(&a.Y, &b.Y)
+	t2.Mul(&a.Z, &b.Z)
+	t3.Add(&a.X, &a.Y)
+	t4.Add(&b.X, &b.Y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&a.Y, &a.Z)
+	X3.Add(&b.Y, &b.Z)
+	t4.Mul(&t4, &X3)
+	X3.Add(&t1, &t2)
+	t4.Sub(&t4, &X3)
+	X3.Add(&a.X, &a.Z)
+	Y3.Add(&b.X, &b.Z)
+	X3.Mul(&X3, &Y3)
+	Y3.Add(&t0, &t2)
+	Y3.Sub(&X3, &Y3)
+	X3.Double(&t0)
+	t0.Add(&X3, &t0)
+	t2.Mul(&b3, &t2)
+	Z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	Y3.Mul(&b3, &Y3)
+	X3.Mul(&t4, &Y3)
+	t2.Mul(&t3, &t1)
+	X3.Sub(&t2, &X3)
+	Y3.Mul(&Y3, &t0)
+	t1.Mul(&t1, &Z3)
+	Y3.Add(&t1, &Y3)
+	t0.Mul(&t0, &t3)
+	Z3.Mul(&Z3, &t4)
+	Z3.Add(&Z3, &t0)
+
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
+// double sets p = 2⋅q and returns p
+// complete doubling formula for short Weierstrass curves with a=0, on groups of odd order
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 9)
+func (p *g2ProjCT) double(q *g2ProjCT) *g2ProjCT {
+	var b3, t0, t1, t2, X3, Y3, Z3 fptower.E2
+	b3.Double(&bTwistCurveCoeff).Add(&b3, &bTwistCurveCoeff)
+
+	t0.Square(&q.Y)
+	Z3.Double(&t0).Double(&Z3).Double(&Z3)
+	t1.Mul(&q.Y, &q.Z)
+	t2.Square(&q.Z)
+	t2.Mul(&b3, &t2)
+	X3.Mul(&t2, &Z3)
+	Y3.Add(&t0, &t2)
+	Z3.Mul(&t1, &Z3)
+	t1.Double(&t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	Y3.Mul(&t0, &Y3)
+	Y3.Add(&X3, &Y3)
+	t1.Mul(&q.X, &q.Y)
+	X3.Mul(&t0, &t1)
+	X3.Double(&X3)
+
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
 // -------------------------------------------------------------------------------------------------
 // Homogenous projective
 
@@ -873,6 +1049,56 @@ func (p *g2Proj) FromAffine(Q *G2Affine) *g2Proj {
 	return p
 }
 
+// BatchJacobianToAffineG2 converts points in Jacobian coordinates to Affine coordinates
+// performing a single field inversion (Montgomery batch inversion trick).
+func BatchJacobianToAffineG2(points []G2Jac) []G2Affine {
+	result := make([]G2Affine, len(points))
+	zeroes := make([]bool, len(points))
+	var accumulator fptower.E2
+	accumulator.SetOne()
+
+	// batch invert all points[].Z coordinates with Montgomery batch inversion trick
+	// (stores points[].Z^-1 in result[i].X to avoid allocating a slice of fr.Elements)
+	for i := 0; i < len(points); i++ {
+		if points[i].Z.IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		result[i].X = accumulator
+		accumulator.Mul(&accumulator, &points[i].Z)
+	}
+
+	var accInverse fptower.E2
+	accInverse.Inverse(&accumulator)
+
+	for i := len(points) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			// do nothing, (X=0, Y=0) is infinity point in affine
+			continue
+		}
+		result[i].X.Mul(&result[i].X, &accInverse)
+		accInverse.Mul(&accInverse, &points[i].Z)
+	}
+
+	// batch convert to affine.
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			if zeroes[i] {
+				// do nothing, (X=0, Y=0) is infinity point in affine
+				continue
+			}
+			var a, b fptower.E2
+			a = result[i].X
+			b.Square(&a)
+			result[i].X.Mul(&points[i].X, &b)
+			result[i].Y.Mul(&points[i].Y, &b).
+				Mul(&result[i].Y, &a)
+		}
+	})
+
+	return result
+}
+
 // BatchScalarMultiplicationG2 multiplies the same base by all scalars
 // and return resulting points in affine coordinates
 // uses a simple windowed-NAF like exponentiation algorithm
@@ -914,7 +1140,7 @@ func BatchScalarMultiplicationG2(base *G2Affine, scalars []fr.Element) []G2Affin
 	toReturn := make([]G2Affine, len(scalars))
 
 	// partition the scalars into digits
-	digits, _ := partitionScalars(scalars, c, runtime.NumCPU())
+	digits, _ := partitionScalars(scalars, c, uint64(nbChunks), runtime.NumCPU())
 
 	// for each digit, take value in the base table, double it c time, voilà.
 	parallel.Execute(len(scalars), func(start, end int) {
@@ -16,7 +16,7 @@ package bls12378
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-378/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls12-378/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fptower"
 )
 
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-4.1
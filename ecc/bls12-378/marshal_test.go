@@ -27,8 +27,8 @@ import (
 	"github.com/leanovate/gopter/prop"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-378/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fptower"
 	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
-	"github.com/consensys/gnark-crypto/ecc/bls12-378/internal/fptower"
 )
 
 const (
@@ -50,6 +50,8 @@ func TestEncoder(t *testing.T) {
 	var inH []G2Affine
 	var inI []fp.Element
 	var inJ []fr.Element
+	var inK GT
+	var inL []GT
 
 	// set values of inputs
 	inA = rand.Uint64()
@@ -64,12 +66,16 @@ func TestEncoder(t *testing.T) {
 	inI = make([]fp.Element, 3)
 	inI[2] = inD.X
 	inJ = make([]fr.Element, 0)
+	inK, _ = Pair([]G1Affine{inD}, []G2Affine{inF})
+	inL = make([]GT, 2)
+	inL[0].SetOne()
+	inL[1] = inK
 
 	// encode them, compressed and raw
 	var buf, bufRaw bytes.Buffer
 	enc := NewEncoder(&buf)
 	encRaw := NewEncoder(&bufRaw, RawEncoding())
-	toEncode := []interface{}{inA, &inB, &inC, &inD, &inE, &inF, inG, inH, inI, inJ}
+	toEncode := []interface{}{inA, &inB, &inC, &inD, &inE, &inF, inG, inH, inI, inJ, &inK, inL}
 	for _, v := range toEncode {
 		if err := enc.Encode(v); err != nil {
 			t.Fatal(err)
@@ -93,8 +99,10 @@ func TestEncoder(t *testing.T) {
 		var outH []G2Affine
 		var outI []fp.Element
 		var outJ []fr.Element
+		var outK GT
+		var outL []GT
 
-		toDecode := []interface{}{&outA, &outB, &outC, &outD, &outE, &outF, &outG, &outH, &outI, &outJ}
+		toDecode := []interface{}{&outA, &outB, &outC, &outD, &outE, &outF, &outG, &outH, &outI, &outJ, &outK, &outL}
 		for _, v := range toDecode {
 			if err := dec.Decode(v); err != nil {
 				t.Fatal(err)
@@ -131,6 +139,17 @@ func TestEncoder(t *testing.T) {
 				t.Fatal("decode(encode(slice(elements))) failed")
 			}
 		}
+		if !inK.Equal(&outK) {
+			t.Fatal("decode(encode(GT) failed")
+		}
+		if len(inL) != len(outL) {
+			t.Fatal("decode(encode(slice(GT))) failed")
+		}
+		for i := 0; i < len(inL); i++ {
+			if !inL[i].Equal(&outL[i]) {
+				t.Fatal("decode(encode(slice(GT))) failed")
+			}
+		}
 		if n != dec.BytesRead() {
 			t.Fatal("bytes read don't match bytes written")
 		}
@@ -142,6 +161,67 @@ func TestEncoder(t *testing.T) {
 
 }
 
+func TestGTSerialization(t *testing.T) {
+	t.Parallel()
+
+	var a big.Int
+	a.SetUint64(rand.Uint64())
+	var g1 G1Affine
+	g1.ScalarMultiplication(&g1GenAff, &a)
+	z, err := Pair([]G1Affine{g1}, []G2Affine{g2GenAff})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var one GT
+	one.SetOne()
+	for _, in := range []GT{z, one} {
+		buf, err := in.CompressedBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out GT
+		if err = out.SetCompressedBytes(buf[:]); err != nil {
+			t.Fatal(err)
+		}
+		if !out.Equal(&in) {
+			t.Fatal("SetCompressedBytes(CompressedBytes()) failed")
+		}
+	}
+
+	// invalid encodings
+	var out GT
+	buf, _ := z.CompressedBytes()
+	if err = out.SetCompressedBytes(buf[:SizeOfGTCompressed-1]); err == nil {
+		t.Fatal("short buffer should be rejected")
+	}
+	// an element of the torus which is not in GT
+	var w, wInv GT
+	w.SetRandom()
+	wInv.Inverse(&w)
+	w.Conjugate(&w).Mul(&w, &wInv)
+	buf, err = w.CompressedBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.SetCompressedBytes(buf[:]); err == nil {
+		t.Fatal("element not in GT should be rejected")
+	}
+	var bufEnc bytes.Buffer
+	if err = NewEncoder(&bufEnc, RawEncoding()).Encode(&w); err != nil {
+		t.Fatal(err)
+	}
+	if err = NewDecoder(&bufEnc).Decode(&out); err == nil {
+		t.Fatal("element not in GT should be rejected")
+	}
+	// -1 can't be compressed
+	var minusOne GT
+	minusOne.Sub(&minusOne, &one)
+	if _, err = minusOne.CompressedBytes(); err == nil {
+		t.Fatal("-1 should not be compressed")
+	}
+}
+
 func TestIsCompressed(t *testing.T) {
 	t.Parallel()
 	var g1Inf, g1 G1Affine
@@ -18,7 +18,7 @@ package bls12378
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-378/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls12-378/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fptower"
 )
 
 type batchOpG1Affine struct {
@@ -0,0 +1,205 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12378
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+)
+
+func TestMultiExpStreamG1(t *testing.T) {
+	t.Parallel()
+	const nbBases = 73
+
+	var scalars [nbBases]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	bases := BatchScalarMultiplicationG1(&g1GenAff, scalars[:])
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	// a base at infinity
+	bases[3].X.SetZero()
+	bases[3].Y.SetZero()
+
+	var raw, compressed bytes.Buffer
+	if err := NewEncoder(&raw, RawEncoding()).Encode(bases); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEncoder(&compressed).Encode(bases); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, expectedPrefix G1Affine
+	if _, err := expected.MultiExp(bases, scalars[:], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expectedPrefix.MultiExp(bases[:nbBases/2], scalars[:nbBases/2], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, batchSize := range []int{1, 7, nbBases, 0} {
+		var res G1Affine
+		dec := NewDecoder(bytes.NewReader(raw.Bytes()))
+		if _, err := res.MultiExpStream(dec, scalars[:], batchSize, ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expected) {
+			t.Fatalf("batch size %d: MultiExpStream doesn't match MultiExp", batchSize)
+		}
+		if dec.BytesRead() != int64(raw.Len()) {
+			t.Fatal("all the bases should have been read")
+		}
+
+		dec = NewDecoder(bytes.NewReader(raw.Bytes()), NoSubgroupChecks())
+		if _, err := res.MultiExpStream(dec, scalars[:nbBases/2], batchSize, ecc.MultiExpConfig{NbTasks: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expectedPrefix) {
+			t.Fatalf("batch size %d: MultiExpStream over a prefix of the bases doesn't match MultiExp", batchSize)
+		}
+	}
+
+	// invalid inputs
+	var res G1Affine
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(raw.Bytes())), append(scalars[:], scalars[0]), 8, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("more scalars than bases should be rejected")
+	}
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(raw.Bytes()[:raw.Len()-1])), scalars[:], 8, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("truncated stream should be rejected")
+	}
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(compressed.Bytes())), scalars[:], 8, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("compressed bases should be rejected")
+	}
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(raw.Bytes())), scalars[:], 8, ecc.MultiExpConfig{NbTasks: 1025}); err == nil {
+		t.Fatal("invalid config should be rejected")
+	}
+
+	// a base not on the curve in the second batch: the bases are read up to the end
+	// of this batch and the decoder can be used again once MultiExpStream returns
+	const batchSize = 8
+	corrupted := append([]byte{}, raw.Bytes()...)
+	corrupted[4+(batchSize+2)*SizeOfG1AffineUncompressed-1] ^= 1
+	dec := NewDecoder(bytes.NewReader(corrupted))
+	if _, err := res.MultiExpStream(dec, scalars[:], batchSize, ecc.MultiExpConfig{}); err != errRawBases {
+		t.Fatal("expected errRawBases, got", err)
+	}
+	if dec.BytesRead() != 4+2*batchSize*SizeOfG1AffineUncompressed {
+		t.Fatal("the bases should have been read up to the end of the invalid batch")
+	}
+	var next G1Affine
+	if err := dec.Decode(&next); err != nil {
+		t.Fatal(err)
+	}
+	if !next.Equal(&bases[2*batchSize]) {
+		t.Fatal("the decoder should be positioned after the invalid batch")
+	}
+}
+
+func TestMultiExpStreamG2(t *testing.T) {
+	t.Parallel()
+	const nbBases = 73
+
+	var scalars [nbBases]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	bases := BatchScalarMultiplicationG2(&g2GenAff, scalars[:])
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	// a base at infinity
+	bases[3].X.SetZero()
+	bases[3].Y.SetZero()
+
+	var raw, compressed bytes.Buffer
+	if err := NewEncoder(&raw, RawEncoding()).Encode(bases); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEncoder(&compressed).Encode(bases); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, expectedPrefix G2Affine
+	if _, err := expected.MultiExp(bases, scalars[:], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expectedPrefix.MultiExp(bases[:nbBases/2], scalars[:nbBases/2], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, batchSize := range []int{1, 7, nbBases, 0} {
+		var res G2Affine
+		dec := NewDecoder(bytes.NewReader(raw.Bytes()))
+		if _, err := res.MultiExpStream(dec, scalars[:], batchSize, ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expected) {
+			t.Fatalf("batch size %d: MultiExpStream doesn't match MultiExp", batchSize)
+		}
+		if dec.BytesRead() != int64(raw.Len()) {
+			t.Fatal("all the bases should have been read")
+		}
+
+		dec = NewDecoder(bytes.NewReader(raw.Bytes()), NoSubgroupChecks())
+		if _, err := res.MultiExpStream(dec, scalars[:nbBases/2], batchSize, ecc.MultiExpConfig{NbTasks: 2}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expectedPrefix) {
+			t.Fatalf("batch size %d: MultiExpStream over a prefix of the bases doesn't match MultiExp", batchSize)
+		}
+	}
+
+	// invalid inputs
+	var res G2Affine
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(raw.Bytes())), append(scalars[:], scalars[0]), 8, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("more scalars than bases should be rejected")
+	}
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(raw.Bytes()[:raw.Len()-1])), scalars[:], 8, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("truncated stream should be rejected")
+	}
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(compressed.Bytes())), scalars[:], 8, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("compressed bases should be rejected")
+	}
+	if _, err := res.MultiExpStream(NewDecoder(bytes.NewReader(raw.Bytes())), scalars[:], 8, ecc.MultiExpConfig{NbTasks: 1025}); err == nil {
+		t.Fatal("invalid config should be rejected")
+	}
+
+	// a base not on the curve in the second batch: the bases are read up to the end
+	// of this batch and the decoder can be used again once MultiExpStream returns
+	const batchSize = 8
+	corrupted := append([]byte{}, raw.Bytes()...)
+	corrupted[4+(batchSize+2)*SizeOfG2AffineUncompressed-1] ^= 1
+	dec := NewDecoder(bytes.NewReader(corrupted))
+	if _, err := res.MultiExpStream(dec, scalars[:], batchSize, ecc.MultiExpConfig{}); err != errRawBases {
+		t.Fatal("expected errRawBases, got", err)
+	}
+	if dec.BytesRead() != 4+2*batchSize*SizeOfG2AffineUncompressed {
+		t.Fatal("the bases should have been read up to the end of the invalid batch")
+	}
+	var next G2Affine
+	if err := dec.Decode(&next); err != nil {
+		t.Fatal(err)
+	}
+	if !next.Equal(&bases[2*batchSize]) {
+		t.Fatal("the decoder should be positioned after the invalid batch")
+	}
+}
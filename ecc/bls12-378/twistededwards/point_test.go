@@ -709,6 +709,26 @@ func TestOps(t *testing.T) {
 		genS1,
 	))
 
+	properties.Property("constant-time scalar multiplication should be consistent with scalar multiplication", prop.ForAll(
+		func(s big.Int) bool {
+
+			params := GetEdwardsCurve()
+
+			var baseProj, ctProj PointProj
+			var p1, p2, p3, p4 PointAffine
+			baseProj.FromAffine(&params.Base)
+			ctProj.ScalarMultiplicationCT(&baseProj, &s)
+			baseProj.ScalarMultiplication(&baseProj, &s)
+			p1.FromProj(&baseProj)
+			p2.FromProj(&ctProj)
+			p3.ScalarMultiplicationCT(&params.Base, &s)
+			p4.ScalarMultiplicationCT(&params.Base, &params.Order)
+
+			return p1.Equal(&p2) && p1.Equal(&p3) && p4.IsZero()
+		},
+		genS1,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
@@ -779,3 +799,18 @@ func BenchmarkScalarMulProjective(b *testing.B) {
 		doubleAndAdd.ScalarMultiplication(&a, &s)
 	}
 }
+
+func BenchmarkScalarMulProjectiveCT(b *testing.B) {
+	params := GetEdwardsCurve()
+	var a PointProj
+	var s big.Int
+	a.FromAffine(&params.Base)
+	s.SetString("52435875175126190479447705081859658376581184513", 10)
+
+	var ct PointProj
+
+	b.ResetTimer()
+	for j := 0; j < b.N; j++ {
+		ct.ScalarMultiplicationCT(&a, &s)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"hash"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// AggregateSignatures aggregates signatures produced under the scheme s
+// into a single signature of the same size.
+func AggregateSignatures(s Scheme, signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errNoSignature
+	}
+
+	if s.Variant == MinSig {
+		var acc bls12381.G1Jac
+		var sig bls12381.G1Affine
+		for i := range signatures {
+			if _, err := sig.SetBytes(signatures[i]); err != nil {
+				return nil, errInvalidSignature
+			}
+			acc.AddMixed(&sig)
+		}
+		sig.FromJacobian(&acc)
+		res := sig.Bytes()
+		return res[:], nil
+	}
+
+	var acc bls12381.G2Jac
+	var sig bls12381.G2Affine
+	for i := range signatures {
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return nil, errInvalidSignature
+		}
+		acc.AddMixed(&sig)
+	}
+	sig.FromJacobian(&acc)
+	res := sig.Bytes()
+	return res[:], nil
+}
+
+// AggregatePublicKeys aggregates public keys sharing the same scheme
+// into a single public key. The result is only meaningful for
+// FastAggregateVerify in the proof of possession scheme.
+func AggregatePublicKeys(pks []PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, errNoPublicKey
+	}
+	var res PublicKey
+	res.Scheme = pks[0].Scheme
+	var acc1 bls12381.G1Jac
+	var acc2 bls12381.G2Jac
+	for i := range pks {
+		if pks[i].Scheme != res.Scheme {
+			return nil, errSchemeMismatch
+		}
+		if !pks[i].isValid() {
+			return nil, errInvalidPublicKey
+		}
+		if res.Scheme.Variant == MinSig {
+			acc2.AddMixed(&pks[i].A2)
+		} else {
+			acc1.AddMixed(&pks[i].A1)
+		}
+	}
+	if res.Scheme.Variant == MinSig {
+		res.A2.FromJacobian(&acc2)
+	} else {
+		res.A1.FromJacobian(&acc1)
+	}
+	return &res, nil
+}
+
+// AggregateVerify verifies an aggregate signature of messages[i] by pks[i].
+// All public keys must share the same scheme. In the basic scheme the
+// messages must be distinct. If hFunc is not nil, each message is first
+// hashed with it, as in Sign.
+//
+// The verification uses a single multi-pairing check.
+func AggregateVerify(pks []PublicKey, messages [][]byte, sig []byte, hFunc hash.Hash) (bool, error) {
+	if len(pks) == 0 {
+		return false, errNoPublicKey
+	}
+	if len(pks) != len(messages) {
+		return false, errInconsistentInput
+	}
+	s := pks[0].Scheme
+
+	msgs := make([][]byte, len(messages))
+	seen := make(map[string]struct{}, len(messages))
+	for i := range messages {
+		if pks[i].Scheme != s {
+			return false, errSchemeMismatch
+		}
+		msg, err := prehash(messages[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		switch s.Mode {
+		case Basic:
+			if _, ok := seen[string(msg)]; ok {
+				return false, errDuplicateMessage
+			}
+			seen[string(msg)] = struct{}{}
+		case MessageAugmentation:
+			msg = augment(&pks[i], msg)
+		}
+		msgs[i] = msg
+	}
+
+	return coreAggregateVerify(pks, msgs, sig, s.DST())
+}
+
+// FastAggregateVerify verifies an aggregate signature of the same message by all pks.
+// It is only defined for the proof of possession scheme: the caller is responsible
+// for having verified a proof of possession for each public key beforehand
+// (see VerifyPossession).
+func FastAggregateVerify(pks []PublicKey, message, sig []byte, hFunc hash.Hash) (bool, error) {
+	if len(pks) == 0 {
+		return false, errNoPublicKey
+	}
+	if pks[0].Scheme.Mode != ProofOfPossession {
+		return false, errNotPoP
+	}
+	apk, err := AggregatePublicKeys(pks)
+	if err != nil {
+		return false, err
+	}
+	return apk.Verify(sig, message, hFunc)
+}
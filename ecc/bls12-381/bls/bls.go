@@ -0,0 +1,379 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/signature"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	errInvalidPublicKey  = errors.New("invalid public key")
+	errInvalidSignature  = errors.New("invalid signature")
+	errInvalidPrivateKey = errors.New("invalid private key")
+	errShortIKM          = errors.New("input keying material must be at least 32 bytes")
+	errSchemeMismatch    = errors.New("public keys use different schemes")
+	errNotPoP            = errors.New("operation requires the proof of possession scheme")
+	errNoPublicKey       = errors.New("no public key")
+	errNoSignature       = errors.New("no signature")
+	errInconsistentInput = errors.New("number of public keys and messages differ")
+	errDuplicateMessage  = errors.New("messages must be distinct in the basic scheme")
+)
+
+const (
+	sizeFr = fr.Bytes
+	sizeG1 = bls12381.SizeOfG1AffineCompressed
+	sizeG2 = bls12381.SizeOfG2AffineCompressed
+)
+
+// Variant selects the groups in which public keys and signatures live.
+type Variant uint8
+
+const (
+	// MinPk minimizes the public key size: public keys are in G1, signatures in G2.
+	MinPk Variant = iota
+	// MinSig minimizes the signature size: signatures are in G1, public keys in G2.
+	MinSig
+)
+
+// Mode is the mechanism protecting aggregate signatures against rogue key attacks.
+type Mode uint8
+
+const (
+	// Basic requires all messages of an aggregate signature to be distinct.
+	Basic Mode = iota
+	// MessageAugmentation prepends the signer's public key to the message.
+	MessageAugmentation
+	// ProofOfPossession requires a proof of possession of each public key.
+	ProofOfPossession
+)
+
+// Scheme is one of the six ciphersuites of the draft (§4.2).
+// The zero value is the basic min-pk ciphersuite.
+type Scheme struct {
+	Variant Variant
+	Mode    Mode
+}
+
+// DST returns the ciphersuite ID, used as the domain separation tag when hashing messages to the curve.
+func (s Scheme) DST() []byte {
+	return []byte("BLS_SIG_" + s.group() + "_XMD:SHA-256_SSWU_RO_" + s.suffix() + "_")
+}
+
+// popDST returns the domain separation tag used to hash public keys in proofs of possession.
+func (s Scheme) popDST() []byte {
+	return []byte("BLS_POP_" + s.group() + "_XMD:SHA-256_SSWU_RO_POP_")
+}
+
+// group returns the name of the group where signatures live.
+func (s Scheme) group() string {
+	if s.Variant == MinSig {
+		return "BLS12381G1"
+	}
+	return "BLS12381G2"
+}
+
+func (s Scheme) suffix() string {
+	switch s.Mode {
+	case MessageAugmentation:
+		return "AUG"
+	case ProofOfPossession:
+		return "POP"
+	default:
+		return "NUL"
+	}
+}
+
+// sizePublicKey returns the size in bytes of a compressed public key.
+func (s Scheme) sizePublicKey() int {
+	if s.Variant == MinSig {
+		return sizeG2
+	}
+	return sizeG1
+}
+
+// sizeSignature returns the size in bytes of a compressed signature.
+func (s Scheme) sizeSignature() int {
+	if s.Variant == MinSig {
+		return sizeG1
+	}
+	return sizeG2
+}
+
+// PublicKey bls public key, x⋅G where x is the secret scalar and G
+// the generator of G1 (min-pk) or G2 (min-sig).
+type PublicKey struct {
+	Scheme Scheme
+	A1     bls12381.G1Affine // public key when Scheme.Variant == MinPk
+	A2     bls12381.G2Affine // public key when Scheme.Variant == MinSig
+}
+
+// PrivateKey private key of a bls instance
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// GenerateKey generates a public and private key pair for the scheme s,
+// reading 32 bytes of input keying material from r.
+func GenerateKey(s Scheme, r io.Reader) (*PrivateKey, error) {
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(r, ikm); err != nil {
+		return nil, err
+	}
+	return KeyGen(s, ikm, nil)
+}
+
+// KeyGen deterministically derives a key pair for the scheme s from the
+// secret input keying material ikm (at least 32 bytes) and the optional keyInfo,
+// as in the draft (§2.3).
+func KeyGen(s Scheme, ikm, keyInfo []byte) (*PrivateKey, error) {
+	if len(ikm) < 32 {
+		return nil, errShortIKM
+	}
+
+	// L = ceil((3 * ceil(log2(r))) / 16)
+	const L = 48
+
+	ikm0 := make([]byte, len(ikm)+1)
+	copy(ikm0, ikm)
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	info[len(keyInfo)] = L >> 8
+	info[len(keyInfo)+1] = L & 0xff
+
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	okm := make([]byte, L)
+	var sk big.Int
+	for sk.BitLen() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, ikm0, salt)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm).Mod(&sk, fr.Modulus())
+	}
+
+	var priv PrivateKey
+	sk.FillBytes(priv.scalar[:])
+	priv.PublicKey.Scheme = s
+	priv.setPublicKey()
+
+	return &priv, nil
+}
+
+// setPublicKey computes the public key from the secret scalar.
+func (privKey *PrivateKey) setPublicKey() {
+	var sk big.Int
+	sk.SetBytes(privKey.scalar[:])
+	_, _, g1, g2 := bls12381.Generators()
+	if privKey.PublicKey.Scheme.Variant == MinSig {
+		privKey.PublicKey.A2.ScalarMultiplicationCT(&g2, &sk)
+	} else {
+		privKey.PublicKey.A1.ScalarMultiplicationCT(&g1, &sk)
+	}
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	if pub.Scheme != xx.Scheme {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.Scheme = privKey.PublicKey.Scheme
+	pub.A1.Set(&privKey.PublicKey.A1)
+	pub.A2.Set(&privKey.PublicKey.A2)
+	return &pub
+}
+
+// Sign signs a message following the scheme of the key.
+// If hFunc is not nil, the message is first hashed with it.
+// The (pre-hashed) message is then hashed to the curve with
+// the ciphersuite's hash_to_curve.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	s := privKey.PublicKey.Scheme
+	msg, err := prehash(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	if s.Mode == MessageAugmentation {
+		msg = augment(&privKey.PublicKey, msg)
+	}
+	return privKey.coreSign(msg, s.DST())
+}
+
+// ProvePossession returns a proof of possession of the private key.
+// It is only defined for the proof of possession scheme.
+func (privKey *PrivateKey) ProvePossession() ([]byte, error) {
+	s := privKey.PublicKey.Scheme
+	if s.Mode != ProofOfPossession {
+		return nil, errNotPoP
+	}
+	return privKey.coreSign(privKey.PublicKey.Bytes(), s.popDST())
+}
+
+// coreSign hashes msg to the signature group with the domain separation tag dst
+// and multiplies the result by the secret scalar.
+func (privKey *PrivateKey) coreSign(msg, dst []byte) ([]byte, error) {
+	var sk big.Int
+	sk.SetBytes(privKey.scalar[:])
+
+	if privKey.PublicKey.Scheme.Variant == MinSig {
+		q, err := bls12381.HashToG1(msg, dst)
+		if err != nil {
+			return nil, err
+		}
+		q.ScalarMultiplicationCT(&q, &sk)
+		res := q.Bytes()
+		return res[:], nil
+	}
+
+	q, err := bls12381.HashToG2(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	q.ScalarMultiplicationCT(&q, &sk)
+	res := q.Bytes()
+	return res[:], nil
+}
+
+// Verify verifies a bls signature following the scheme of the public key.
+// If hFunc is not nil, the message is first hashed with it, as in Sign.
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	msg, err := prehash(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+	if pub.Scheme.Mode == MessageAugmentation {
+		msg = augment(pub, msg)
+	}
+	return coreAggregateVerify([]PublicKey{*pub}, [][]byte{msg}, sigBin, pub.Scheme.DST())
+}
+
+// VerifyPossession verifies a proof of possession of the private key
+// associated to pub. It is only defined for the proof of possession scheme.
+func (pub *PublicKey) VerifyPossession(proof []byte) (bool, error) {
+	if pub.Scheme.Mode != ProofOfPossession {
+		return false, errNotPoP
+	}
+	return coreAggregateVerify([]PublicKey{*pub}, [][]byte{pub.Bytes()}, proof, pub.Scheme.popDST())
+}
+
+// isValid implements KeyValidate: the public key must be in the
+// prime order subgroup and not be the point at infinity.
+func (pub *PublicKey) isValid() bool {
+	if pub.Scheme.Variant == MinSig {
+		return !pub.A2.IsInfinity() && pub.A2.IsInSubGroup()
+	}
+	return !pub.A1.IsInfinity() && pub.A1.IsInSubGroup()
+}
+
+// coreAggregateVerify checks that sigBin is a valid aggregate signature of msgs[i] by pks[i],
+// i.e. ∏ᵢ e(pkᵢ, H(msgᵢ)) == e(G, sig) (roles of G1 and G2 swapped in min-sig).
+// All public keys must share the same scheme.
+func coreAggregateVerify(pks []PublicKey, msgs [][]byte, sigBin, dst []byte) (bool, error) {
+	if len(pks) == 0 {
+		return false, errNoPublicKey
+	}
+	if len(pks) != len(msgs) {
+		return false, errInconsistentInput
+	}
+	s := pks[0].Scheme
+	for i := range pks {
+		if pks[i].Scheme != s {
+			return false, errSchemeMismatch
+		}
+		if !pks[i].isValid() {
+			return false, errInvalidPublicKey
+		}
+	}
+
+	n := len(pks) + 1
+	P := make([]bls12381.G1Affine, n)
+	Q := make([]bls12381.G2Affine, n)
+	_, _, g1, g2 := bls12381.Generators()
+
+	if s.Variant == MinSig {
+		if _, err := P[n-1].SetBytes(sigBin); err != nil {
+			return false, errInvalidSignature
+		}
+		Q[n-1].Neg(&g2)
+		for i := range pks {
+			h, err := bls12381.HashToG1(msgs[i], dst)
+			if err != nil {
+				return false, err
+			}
+			P[i] = h
+			Q[i].Set(&pks[i].A2)
+		}
+	} else {
+		if _, err := Q[n-1].SetBytes(sigBin); err != nil {
+			return false, errInvalidSignature
+		}
+		P[n-1].Neg(&g1)
+		for i := range pks {
+			h, err := bls12381.HashToG2(msgs[i], dst)
+			if err != nil {
+				return false, err
+			}
+			P[i].Set(&pks[i].A1)
+			Q[i] = h
+		}
+	}
+
+	return bls12381.PairingCheck(P, Q)
+}
+
+// prehash returns hFunc(message) if hFunc is not nil, message otherwise.
+func prehash(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// augment returns pub||msg
+func augment(pub *PublicKey, msg []byte) []byte {
+	pkBin := pub.Bytes()
+	res := make([]byte, len(pkBin)+len(msg))
+	copy(res, pkBin)
+	copy(res[len(pkBin):], msg)
+	return res
+}
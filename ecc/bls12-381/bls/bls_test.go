@@ -0,0 +1,336 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+var schemes = []Scheme{
+	{MinPk, Basic},
+	{MinPk, MessageAugmentation},
+	{MinPk, ProofOfPossession},
+	{MinSig, Basic},
+	{MinSig, MessageAugmentation},
+	{MinSig, ProofOfPossession},
+}
+
+func Example() {
+	scheme := Scheme{Variant: MinPk, Mode: ProofOfPossession}
+
+	// create a bls key pair
+	privateKey, _ := GenerateKey(scheme, crand.Reader)
+	publicKey := privateKey.PublicKey
+
+	// sign the message
+	msg := []byte("message")
+	signature, _ := privateKey.Sign(msg, nil)
+
+	// verifies signature
+	isValid, _ := publicKey.Verify(signature, msg, nil)
+	if !isValid {
+		fmt.Println("1. invalid signature")
+	} else {
+		fmt.Println("1. valid signature")
+	}
+
+	// Output: 1. valid signature
+}
+
+func TestSignVerify(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- test only
+
+	for _, s := range schemes {
+		privKey, err := GenerateKey(s, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey := privKey.PublicKey
+
+		for _, hFunc := range []bool{false, true} {
+			h := sha256.New()
+			if !hFunc {
+				h = nil
+			}
+			sig, err := privKey.Sign([]byte("message"), h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sig) != s.sizeSignature() {
+				t.Fatal("wrong signature size")
+			}
+
+			// verifies correct msg
+			res, err := pubKey.Verify(sig, []byte("message"), h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res {
+				t.Fatalf("%s: verify correct signature should return true", s.DST())
+			}
+
+			// verifies wrong msg
+			res, err = pubKey.Verify(sig, []byte("wrong_message"), h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res {
+				t.Fatalf("%s: verify wrong signature should be false", s.DST())
+			}
+		}
+
+		// a signature from another scheme must not verify
+		other := *privKey
+		other.PublicKey.Scheme.Mode = (s.Mode + 1) % 3
+		other.setPublicKey()
+		sig, err := other.Sign([]byte("message"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res, _ := pubKey.Verify(sig, []byte("message"), nil); res {
+			t.Fatalf("%s: signature from another ciphersuite should be rejected", s.DST())
+		}
+	}
+}
+
+func TestAggregateVerify(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- test only
+	const n = 4
+
+	for _, s := range schemes {
+		pks := make([]PublicKey, n)
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			privKey, err := GenerateKey(s, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pks[i] = privKey.PublicKey
+			msgs[i] = []byte(fmt.Sprintf("message %d", i))
+			sigs[i], err = privKey.Sign(msgs[i], nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		aggSig, err := AggregateSignatures(s, sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := AggregateVerify(pks, msgs, aggSig, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatalf("%s: aggregate verify should return true", s.DST())
+		}
+
+		msgs[1], msgs[2] = msgs[2], msgs[1]
+		res, err = AggregateVerify(pks, msgs, aggSig, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res {
+			t.Fatalf("%s: aggregate verify with swapped messages should return false", s.DST())
+		}
+
+		msgs[1] = msgs[2]
+		_, err = AggregateVerify(pks, msgs, aggSig, nil)
+		if s.Mode == Basic && err != errDuplicateMessage {
+			t.Fatalf("%s: duplicate messages should be rejected", s.DST())
+		}
+	}
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- test only
+	const n = 4
+
+	for _, v := range []Variant{MinPk, MinSig} {
+		s := Scheme{Variant: v, Mode: ProofOfPossession}
+		msg := []byte("message")
+		pks := make([]PublicKey, n)
+		sigs := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			privKey, err := GenerateKey(s, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pks[i] = privKey.PublicKey
+
+			proof, err := privKey.ProvePossession()
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := pks[i].VerifyPossession(proof)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res {
+				t.Fatal("verify proof of possession should return true")
+			}
+			// a signature on the public key is not a proof of possession
+			sig, _ := privKey.Sign(pks[i].Bytes(), nil)
+			if res, _ := pks[i].VerifyPossession(sig); res {
+				t.Fatal("signature of the public key should not be a valid proof of possession")
+			}
+
+			sigs[i], err = privKey.Sign(msg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		aggSig, err := AggregateSignatures(s, sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := FastAggregateVerify(pks, msg, aggSig, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("fast aggregate verify should return true")
+		}
+
+		res, err = FastAggregateVerify(pks[1:], msg, aggSig, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res {
+			t.Fatal("fast aggregate verify with a missing public key should return false")
+		}
+	}
+
+	if _, err := FastAggregateVerify([]PublicKey{{}}, nil, nil, nil); err != errNotPoP {
+		t.Fatal("fast aggregate verify should require the proof of possession scheme")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- test only
+
+	for _, s := range schemes {
+		privKey1, err := GenerateKey(s, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey1 := privKey1.PublicKey
+
+		var pubKey2 PublicKey
+		pubKey2.Scheme = s
+		if _, err := pubKey2.SetBytes(pubKey1.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if !pubKey1.Equal(&pubKey2) {
+			t.Fatal("Error serialize(deserialize(.))")
+		}
+
+		var privKey2 PrivateKey
+		privKey2.PublicKey.Scheme = s
+		if _, err := privKey2.SetBytes(privKey1.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(privKey1.Bytes(), privKey2.Bytes()) {
+			t.Fatal("Error serialize(deserialize(.))")
+		}
+
+		// the point at infinity is not a valid public key
+		var infinity PublicKey
+		infinity.Scheme = s
+		if _, err := pubKey2.SetBytes(infinity.Bytes()); err == nil {
+			t.Fatal("point at infinity should be rejected")
+		}
+	}
+}
+
+func TestKeyGen(t *testing.T) {
+	if _, err := KeyGen(Scheme{}, make([]byte, 31), nil); err != errShortIKM {
+		t.Fatal("short IKM should be rejected")
+	}
+
+	ikm := make([]byte, 32)
+	privKey1, err := KeyGen(Scheme{}, ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey2, err := KeyGen(Scheme{}, ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if privKey1.scalar != privKey2.scalar {
+		t.Fatal("KeyGen should be deterministic")
+	}
+	privKey2, err = KeyGen(Scheme{}, ikm, []byte("info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if privKey1.scalar == privKey2.scalar {
+		t.Fatal("key_info should change the key")
+	}
+}
+
+// TestEthereumVector checks a signature from the Ethereum consensus-spec
+// test vectors, which use the min-pk proof of possession ciphersuite.
+func TestEthereumVector(t *testing.T) {
+	s := Scheme{Variant: MinPk, Mode: ProofOfPossession}
+	var privKey PrivateKey
+	privKey.PublicKey.Scheme = s
+	sk, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	copy(privKey.scalar[:], sk)
+	privKey.setPublicKey()
+
+	expected, _ := hex.DecodeString("b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55")
+	sig, err := privKey.Sign(make([]byte, 32), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, expected) {
+		t.Fatal("signature doesn't match test vector")
+	}
+}
+
+// benchmarks
+
+func BenchmarkSign(b *testing.B) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- test only
+	privKey, _ := GenerateKey(Scheme{}, r)
+	msg := []byte("message")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 -- test only
+	privKey, _ := GenerateKey(Scheme{}, r)
+	pubKey := privKey.PublicKey
+	msg := []byte("message")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = pubKey.Verify(sig, msg, nil)
+	}
+}
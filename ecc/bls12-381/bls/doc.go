@@ -0,0 +1,26 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bls provides BLS signatures on bls12-381.
+//
+// It implements the ciphersuites of the IETF draft (basic, message augmentation
+// and proof of possession), each in the minimal-pubkey-size variant (public keys
+// in G1, signatures in G2) and the minimal-signature-size variant (signatures in
+// G1, public keys in G2). Points are serialized in the compressed form of
+// bls12381.G1Affine.Bytes and bls12381.G2Affine.Bytes.
+//
+// # See also
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+package bls
@@ -0,0 +1,97 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"crypto/subtle"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Bytes returns the binary representation of the public key,
+// that is the compressed encoding of the G1 (min-pk) or G2 (min-sig) point.
+func (pk *PublicKey) Bytes() []byte {
+	if pk.Scheme.Variant == MinSig {
+		res := pk.A2.Bytes()
+		return res[:]
+	}
+	res := pk.A1.Bytes()
+	return res[:]
+}
+
+// SetBytes sets pk from the compressed point encoding in buf.
+// The variant (hence the expected size) is read from pk.Scheme, which must be
+// set beforehand. The point is checked to be in the prime order subgroup and not
+// to be the point at infinity.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	n := pk.Scheme.sizePublicKey()
+	if len(buf) < n {
+		return 0, io.ErrShortBuffer
+	}
+	var err error
+	if pk.Scheme.Variant == MinSig {
+		_, err = pk.A2.SetBytes(buf[:n])
+	} else {
+		_, err = pk.A1.SetBytes(buf[:n])
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !pk.isValid() {
+		return n, errInvalidPublicKey
+	}
+	return n, nil
+}
+
+// Bytes returns the binary representation of privKey,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	sizePublicKey := privKey.PublicKey.Scheme.sizePublicKey()
+	res := make([]byte, sizePublicKey+sizeFr)
+	pubkBin := privKey.PublicKey.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pubkBin)
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:], privKey.scalar[:])
+	return res
+}
+
+// SetBytes sets privKey from buf, where buf is interpreted
+// as publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The scheme is read from privKey.PublicKey.Scheme, which must be set beforehand.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	sizePublicKey := privKey.PublicKey.Scheme.sizePublicKey()
+	if len(buf) < sizePublicKey+sizeFr {
+		return 0, io.ErrShortBuffer
+	}
+	n, err := privKey.PublicKey.SetBytes(buf[:sizePublicKey])
+	if err != nil {
+		return n, err
+	}
+	var sk big.Int
+	sk.SetBytes(buf[sizePublicKey : sizePublicKey+sizeFr])
+	if sk.Cmp(fr.Modulus()) >= 0 {
+		return n, errInvalidPrivateKey
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[sizePublicKey:sizePublicKey+sizeFr])
+	n += sizeFr
+	return n, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip4844 implements the polynomial commitments of the Ethereum Deneb
+// upgrade (EIP-4844) on top of the KZG commitment scheme of bls12-381/fr/kzg.
+//
+// A blob is a polynomial of degree less than 4096 given by its evaluations on
+// the 4096-th roots of unity ω = 7^((r-1)/4096), in bit-reversed order. The
+// Context holds the trusted setup and exposes the functions of the spec:
+// BlobToKZGCommitment, ComputeKZGProof, ComputeBlobKZGProof, VerifyKZGProof,
+// VerifyBlobKZGProof and VerifyBlobKZGProofBatch.
+//
+// # Encoding
+//
+//   - a field element is a 32-byte big-endian integer, strictly lower than r
+//   - a commitment or a proof is a compressed G1 point (48 bytes), which must be
+//     in the r-torsion subgroup; the point at infinity is 0xc0 followed by zeros
+//
+// The verification functions return nil if the proofs are valid, ErrVerifyProof
+// if they are not, and another error if an input is not correctly encoded.
+//
+// # See also
+//
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+package eip4844
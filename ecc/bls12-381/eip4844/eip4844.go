@@ -0,0 +1,372 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+)
+
+const (
+	// BytesPerFieldElement is the size of an encoded field element
+	BytesPerFieldElement = 32
+	// FieldElementsPerBlob is the number of evaluations of the polynomial of a blob
+	FieldElementsPerBlob = 4096
+	// BytesPerBlob is the size of a blob
+	BytesPerBlob = BytesPerFieldElement * FieldElementsPerBlob
+	// BytesPerCommitment is the size of an encoded commitment
+	BytesPerCommitment = bls12381.SizeOfG1AffineCompressed
+	// BytesPerProof is the size of an encoded proof
+	BytesPerProof = bls12381.SizeOfG1AffineCompressed
+)
+
+// domain separators of the Fiat-Shamir challenges
+const (
+	fiatShamirProtocolDomain = "FSBLOBVERIFY_V1_"
+)
+
+var (
+	// ErrInvalidFieldElement is returned when a field element is not a canonical encoding of an element of 𝔽r
+	ErrInvalidFieldElement = errors.New("eip4844: invalid field element encoding")
+	// ErrInvalidPoint is returned when a commitment or a proof is not a valid encoding of a point of G1
+	ErrInvalidPoint = errors.New("eip4844: invalid G1 point encoding")
+	// ErrInvalidBatchSize is returned when the numbers of blobs, commitments and proofs don't match
+	ErrInvalidBatchSize = errors.New("eip4844: numbers of blobs, commitments and proofs don't match")
+	// ErrInvalidTrustedSetup is returned when the trusted setup can't be used
+	ErrInvalidTrustedSetup = errors.New("eip4844: invalid trusted setup")
+	// ErrVerifyProof is returned when a proof is well encoded but doesn't hold
+	ErrVerifyProof = errors.New("eip4844: can't verify proof")
+)
+
+// Blob is the evaluations of a polynomial on the roots of unity of the domain, in bit-reversed order
+type Blob [BytesPerBlob]byte
+
+// KZGCommitment is the compressed encoding of the commitment to the polynomial of a blob
+type KZGCommitment [BytesPerCommitment]byte
+
+// KZGProof is the compressed encoding of a KZG opening proof
+type KZGProof [BytesPerProof]byte
+
+// Bytes32 is the big-endian encoding of a field element
+type Bytes32 [BytesPerFieldElement]byte
+
+// Context holds the trusted setup and the domain of the blobs.
+//
+// It is safe for concurrent use.
+type Context struct {
+	domain *fft.Domain
+
+	// [Lᵢ(τ)]G₁ in natural order
+	srsLagrange kzg.SRSLagrange
+
+	// [G₁], [G₂, [τ]G₂], used to verify the proofs
+	srsVerify kzg.SRS
+
+	// roots of unity in bit-reversed order
+	rootsBitReversed []fr.Element
+}
+
+// NewContext returns a Context from an SRS in monomial form with at least
+// FieldElementsPerBlob powers of τ in G1, such as the one of the Ethereum KZG
+// ceremony (see kzg.NewSRSFromEthereumTranscript).
+func NewContext(srs *kzg.SRS) (*Context, error) {
+	if len(srs.G1) < FieldElementsPerBlob {
+		return nil, ErrInvalidTrustedSetup
+	}
+	domain := fft.NewDomain(FieldElementsPerBlob)
+	srsLagrange, err := kzg.NewSRSLagrange(srs, domain)
+	if err != nil {
+		return nil, err
+	}
+	return newContext(domain, srsLagrange.G1, srs.G2)
+}
+
+// newContext returns a Context from [Lᵢ(τ)]G₁ in natural order and [G₂, [τ]G₂]
+func newContext(domain *fft.Domain, g1Lagrange []bls12381.G1Affine, g2 [2]bls12381.G2Affine) (*Context, error) {
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+	if !g2[0].Equal(&g2Gen) {
+		return nil, ErrInvalidTrustedSetup
+	}
+
+	ctx := &Context{domain: domain}
+	ctx.srsLagrange.G1 = g1Lagrange
+	ctx.srsVerify.G1 = []bls12381.G1Affine{g1Gen}
+	ctx.srsVerify.G2 = g2
+
+	ctx.rootsBitReversed = make([]fr.Element, FieldElementsPerBlob)
+	ctx.rootsBitReversed[0].SetOne()
+	for i := 1; i < FieldElementsPerBlob; i++ {
+		ctx.rootsBitReversed[i].Mul(&ctx.rootsBitReversed[i-1], &domain.Generator)
+	}
+	fft.BitReverse(ctx.rootsBitReversed)
+
+	return ctx, nil
+}
+
+// BlobToKZGCommitment returns the commitment to the polynomial of blob.
+func (ctx *Context) BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
+	evaluations, err := ctx.blobToPolynomial(blob)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	digest, err := kzg.CommitLagrange(evaluations, &ctx.srsLagrange)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	return KZGCommitment(digest.Bytes()), nil
+}
+
+// ComputeKZGProof returns the proof of the evaluation at z of the polynomial of
+// blob, and the evaluation y.
+func (ctx *Context) ComputeKZGProof(blob *Blob, z Bytes32) (KZGProof, Bytes32, error) {
+	evaluations, err := ctx.blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, Bytes32{}, err
+	}
+	point, err := bytesToField(z)
+	if err != nil {
+		return KZGProof{}, Bytes32{}, err
+	}
+	proof, err := kzg.OpenLagrange(evaluations, point, ctx.domain, &ctx.srsLagrange)
+	if err != nil {
+		return KZGProof{}, Bytes32{}, err
+	}
+	return KZGProof(proof.H.Bytes()), Bytes32(proof.ClaimedValue.Bytes()), nil
+}
+
+// ComputeBlobKZGProof returns the proof of the evaluation of the polynomial of blob at
+// the Fiat-Shamir challenge derived from blob and its commitment, which is not checked
+// to be the commitment of blob.
+func (ctx *Context) ComputeBlobKZGProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	evaluations, err := ctx.blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	if _, err := bytesToPoint(commitment); err != nil {
+		return KZGProof{}, err
+	}
+	z := computeChallenge(blob, commitment)
+	proof, err := kzg.OpenLagrange(evaluations, z, ctx.domain, &ctx.srsLagrange)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	return KZGProof(proof.H.Bytes()), nil
+}
+
+// VerifyKZGProof verifies the proof that the polynomial committed in commitment
+// evaluates to y at z.
+func (ctx *Context) VerifyKZGProof(commitment KZGCommitment, z, y Bytes32, proof KZGProof) error {
+	digest, err := bytesToPoint(commitment)
+	if err != nil {
+		return err
+	}
+	point, err := bytesToField(z)
+	if err != nil {
+		return err
+	}
+	var openingProof kzg.OpeningProof
+	if openingProof.ClaimedValue, err = bytesToField(y); err != nil {
+		return err
+	}
+	if openingProof.H, err = bytesToPoint(proof); err != nil {
+		return err
+	}
+	return ctx.verify(&digest, &openingProof, point)
+}
+
+// VerifyBlobKZGProof verifies the proof computed by ComputeBlobKZGProof for blob and commitment.
+func (ctx *Context) VerifyBlobKZGProof(blob *Blob, commitment KZGCommitment, proof KZGProof) error {
+	digest, openingProof, z, err := ctx.blobOpening(blob, commitment, proof)
+	if err != nil {
+		return err
+	}
+	return ctx.verify(&digest, &openingProof, z)
+}
+
+// VerifyBlobKZGProofBatch verifies the proofs computed by ComputeBlobKZGProof for
+// the blobs and commitments, with a single pairing check. It returns nil for an
+// empty batch.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	n := len(blobs)
+	if len(commitments) != n || len(proofs) != n {
+		return ErrInvalidBatchSize
+	}
+	if n == 0 {
+		return nil
+	}
+
+	digests := make([]kzg.Digest, n)
+	openingProofs := make([]kzg.OpeningProof, n)
+	points := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		var err error
+		digests[i], openingProofs[i], points[i], err = ctx.blobOpening(&blobs[i], commitments[i], proofs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := kzg.BatchVerifyMultiPoints(digests, openingProofs, points, &ctx.srsVerify); err != nil {
+		if err == kzg.ErrVerifyOpeningProof {
+			return ErrVerifyProof
+		}
+		return err
+	}
+	return nil
+}
+
+// blobOpening decodes the commitment and the proof of a blob, and returns them with
+// the challenge and the evaluation of the polynomial of the blob at the challenge
+func (ctx *Context) blobOpening(blob *Blob, commitment KZGCommitment, proof KZGProof) (kzg.Digest, kzg.OpeningProof, fr.Element, error) {
+	evaluations, err := ctx.blobToPolynomialBitReversed(blob)
+	if err != nil {
+		return kzg.Digest{}, kzg.OpeningProof{}, fr.Element{}, err
+	}
+	digest, err := bytesToPoint(commitment)
+	if err != nil {
+		return kzg.Digest{}, kzg.OpeningProof{}, fr.Element{}, err
+	}
+	var openingProof kzg.OpeningProof
+	if openingProof.H, err = bytesToPoint(proof); err != nil {
+		return kzg.Digest{}, kzg.OpeningProof{}, fr.Element{}, err
+	}
+	z := computeChallenge(blob, commitment)
+	openingProof.ClaimedValue = ctx.evaluate(evaluations, z)
+
+	return digest, openingProof, z, nil
+}
+
+// verify checks a KZG opening proof
+func (ctx *Context) verify(digest *kzg.Digest, proof *kzg.OpeningProof, point fr.Element) error {
+	if err := kzg.Verify(digest, proof, point, &ctx.srsVerify); err != nil {
+		if err == kzg.ErrVerifyOpeningProof {
+			return ErrVerifyProof
+		}
+		return err
+	}
+	return nil
+}
+
+// evaluate returns the evaluation at z of the polynomial given by its evaluations on
+// the roots of unity in bit-reversed order, with the barycentric formula
+//
+//	p(z) = (zⁿ-1)/n ∑ᵢ ωᵢpᵢ/(z - ωᵢ)
+func (ctx *Context) evaluate(evaluations []fr.Element, z fr.Element) fr.Element {
+	denominators := make([]fr.Element, FieldElementsPerBlob)
+	for i := range denominators {
+		denominators[i].Sub(&z, &ctx.rootsBitReversed[i])
+		if denominators[i].IsZero() {
+			return evaluations[i]
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res, t fr.Element
+	for i := range evaluations {
+		t.Mul(&ctx.rootsBitReversed[i], &denominators[i]).Mul(&t, &evaluations[i])
+		res.Add(&res, &t)
+	}
+	var zn fr.Element
+	zn.Exp(z, big.NewInt(FieldElementsPerBlob))
+	t.SetOne()
+	zn.Sub(&zn, &t).Mul(&zn, &ctx.domain.CardinalityInv)
+	return *res.Mul(&res, &zn)
+}
+
+// blobToPolynomial returns the evaluations of the polynomial of blob, in natural order
+func (ctx *Context) blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	evaluations, err := ctx.blobToPolynomialBitReversed(blob)
+	if err != nil {
+		return nil, err
+	}
+	fft.BitReverse(evaluations)
+	return evaluations, nil
+}
+
+// blobToPolynomialBitReversed returns the evaluations of the polynomial of blob, in the
+// bit-reversed order of the blob
+func (ctx *Context) blobToPolynomialBitReversed(blob *Blob) ([]fr.Element, error) {
+	evaluations := make([]fr.Element, FieldElementsPerBlob)
+	for i := range evaluations {
+		var b Bytes32
+		copy(b[:], blob[i*BytesPerFieldElement:(i+1)*BytesPerFieldElement])
+		var err error
+		if evaluations[i], err = bytesToField(b); err != nil {
+			return nil, err
+		}
+	}
+	return evaluations, nil
+}
+
+// computeChallenge returns the Fiat-Shamir challenge of a blob and its commitment
+//
+//	sha256(FSBLOBVERIFY_V1_ || FieldElementsPerBlob as a 16-byte integer || blob || commitment) mod r
+func computeChallenge(blob *Blob, commitment KZGCommitment) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(fiatShamirProtocolDomain))
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], FieldElementsPerBlob)
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+
+	var z fr.Element
+	z.SetBytes(h.Sum(nil))
+	return z
+}
+
+// bytesToField decodes a canonical big-endian encoding of a field element
+func bytesToField(b Bytes32) (fr.Element, error) {
+	var res fr.Element
+	if err := res.SetBytesCanonical(b[:]); err != nil {
+		return fr.Element{}, ErrInvalidFieldElement
+	}
+	return res, nil
+}
+
+// bytesToPoint decodes a compressed G1 point in the r-torsion subgroup. The point at
+// infinity must be encoded as 0xc0 followed by zeros.
+func bytesToPoint(b [bls12381.SizeOfG1AffineCompressed]byte) (bls12381.G1Affine, error) {
+	const (
+		compressed = 0x80
+		infinity   = 0x40
+	)
+	var p bls12381.G1Affine
+	if b[0]&compressed == 0 {
+		return p, ErrInvalidPoint
+	}
+	if b[0]&infinity != 0 {
+		if b[0] != compressed|infinity {
+			return p, ErrInvalidPoint
+		}
+		for _, v := range b[1:] {
+			if v != 0 {
+				return p, ErrInvalidPoint
+			}
+		}
+		return p, nil
+	}
+	if _, err := p.SetBytes(b[:]); err != nil {
+		return p, ErrInvalidPoint
+	}
+	return p, nil
+}
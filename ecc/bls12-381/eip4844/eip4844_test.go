@@ -0,0 +1,655 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	"gopkg.in/yaml.v3"
+)
+
+// the test vectors of the consensus specs are not vendored: to run them, copy
+// presets/mainnet/trusted_setup_4096.json and tests/general/deneb/kzg of the
+// consensus-spec-tests to testdata/, or set EIP4844_SPEC_TESTS to a directory
+// with the same layout. When EIP4844_SPEC_TESTS is set, missing vectors fail
+// the tests instead of skipping them.
+const (
+	specTestsEnv     = "EIP4844_SPEC_TESTS"
+	trustedSetupFile = "trusted_setup_4096.json"
+	testVectorsDir   = "kzg"
+)
+
+var (
+	testSRS     *kzg.SRS
+	testContext *Context
+	testOnce    sync.Once
+)
+
+// getTestContext returns a Context from an insecure SRS with a known τ
+func getTestContext(t *testing.T) *Context {
+	testOnce.Do(func() {
+		var err error
+		if testSRS, err = kzg.NewSRS(FieldElementsPerBlob, big.NewInt(42)); err != nil {
+			t.Fatal(err)
+		}
+		if testContext, err = NewContext(testSRS); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if testContext == nil {
+		t.Fatal("test context not initialized")
+	}
+	return testContext
+}
+
+// randomBlob returns a random blob, and its polynomial in canonical form
+func randomBlob(t *testing.T) (*Blob, []fr.Element) {
+	evaluations := make([]fr.Element, FieldElementsPerBlob)
+	for i := range evaluations {
+		if _, err := evaluations[i].SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var blob Blob
+	for i := range evaluations {
+		b := evaluations[i].Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+
+	// the blob holds the evaluations in bit-reversed order
+	domain := fft.NewDomain(FieldElementsPerBlob)
+	fft.BitReverse(evaluations)
+	domain.FFTInverse(evaluations, fft.DIF)
+	fft.BitReverse(evaluations)
+	return &blob, evaluations
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	ctx := getTestContext(t)
+	blob, p := randomBlob(t)
+
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := kzg.Commit(p, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment != KZGCommitment(expected.Bytes()) {
+		t.Fatal("wrong commitment")
+	}
+
+	// the commitment of the zero polynomial is the point at infinity
+	commitment, err = ctx.BlobToKZGCommitment(&Blob{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitment != (KZGCommitment{0xc0}) {
+		t.Fatal("expected the point at infinity")
+	}
+
+	// non canonical field element
+	copy(blob[BytesPerFieldElement*7:], fr.Modulus().Bytes())
+	if _, err := ctx.BlobToKZGCommitment(blob); err != ErrInvalidFieldElement {
+		t.Fatalf("expected ErrInvalidFieldElement, got %v", err)
+	}
+}
+
+func TestKZGProof(t *testing.T) {
+	ctx := getTestContext(t)
+	blob, p := randomBlob(t)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a random point, and a root of unity
+	var random, omega fr.Element
+	random.SetRandom()
+	omega.Exp(ctx.domain.Generator, big.NewInt(1234))
+	for _, point := range []fr.Element{random, omega} {
+		z := Bytes32(point.Bytes())
+		proof, y, err := ctx.ComputeKZGProof(blob, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected fr.Element
+		for i := len(p) - 1; i >= 0; i-- {
+			expected.Mul(&expected, &point).Add(&expected, &p[i])
+		}
+		if y != Bytes32(expected.Bytes()) {
+			t.Fatal("wrong evaluation")
+		}
+		if err := ctx.VerifyKZGProof(commitment, z, y, proof); err != nil {
+			t.Fatal(err)
+		}
+
+		y[BytesPerFieldElement-1] ^= 1
+		if err := ctx.VerifyKZGProof(commitment, z, y, proof); err != ErrVerifyProof {
+			t.Fatalf("expected ErrVerifyProof, got %v", err)
+		}
+	}
+
+	// invalid encodings
+	var z Bytes32
+	copy(z[:], fr.Modulus().Bytes())
+	if _, _, err := ctx.ComputeKZGProof(blob, z); err != ErrInvalidFieldElement {
+		t.Fatalf("expected ErrInvalidFieldElement, got %v", err)
+	}
+	proof, y, err := ctx.ComputeKZGProof(blob, Bytes32{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyKZGProof(commitment, z, y, proof); err != ErrInvalidFieldElement {
+		t.Fatalf("expected ErrInvalidFieldElement, got %v", err)
+	}
+	invalid := proof
+	invalid[0] &^= 0x80
+	if err := ctx.VerifyKZGProof(commitment, Bytes32{}, y, invalid); err != ErrInvalidPoint {
+		t.Fatalf("uncompressed: expected ErrInvalidPoint, got %v", err)
+	}
+	invalid = KZGProof{0xc0, 1}
+	if err := ctx.VerifyKZGProof(commitment, Bytes32{}, y, invalid); err != ErrInvalidPoint {
+		t.Fatalf("infinity: expected ErrInvalidPoint, got %v", err)
+	}
+	invalid = KZGProof{0xe0}
+	if err := ctx.VerifyKZGProof(commitment, Bytes32{}, y, invalid); err != ErrInvalidPoint {
+		t.Fatalf("infinity: expected ErrInvalidPoint, got %v", err)
+	}
+}
+
+func TestBlobKZGProof(t *testing.T) {
+	ctx := getTestContext(t)
+
+	const nbBlobs = 3
+	blobs := make([]Blob, nbBlobs)
+	commitments := make([]KZGCommitment, nbBlobs)
+	proofs := make([]KZGProof, nbBlobs)
+	for i := range blobs {
+		blob, _ := randomBlob(t)
+		blobs[i] = *blob
+		var err error
+		if commitments[i], err = ctx.BlobToKZGCommitment(blob); err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i], err = ctx.ComputeBlobKZGProof(blob, commitments[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.VerifyBlobKZGProof(blob, commitments[i], proofs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs[1:]); err != ErrInvalidBatchSize {
+		t.Fatalf("expected ErrInvalidBatchSize, got %v", err)
+	}
+
+	// the proof is bound to the commitment
+	if err := ctx.VerifyBlobKZGProof(&blobs[0], commitments[1], proofs[0]); err != ErrVerifyProof {
+		t.Fatalf("expected ErrVerifyProof, got %v", err)
+	}
+	proofs[1], proofs[2] = proofs[2], proofs[1]
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != ErrVerifyProof {
+		t.Fatalf("expected ErrVerifyProof, got %v", err)
+	}
+	if _, err := ctx.ComputeBlobKZGProof(&blobs[0], KZGCommitment{}); err != ErrInvalidPoint {
+		t.Fatalf("expected ErrInvalidPoint, got %v", err)
+	}
+}
+
+// knownAnswers are the outputs of the functions of the specs on the insecure SRS of
+// getTestContext, for blobs derived from a seed (see seedBlob). They were computed
+// independently from this package, with a direct implementation of the specs where
+// the commitment to p is [p(τ)]G₁, τ being known.
+type knownAnswers struct {
+	Tau   int64
+	Cases []struct {
+		Seed       string
+		Commitment string
+		Proofs     []struct {
+			Z, Y, Proof string
+		}
+		BlobProof string `json:"blob_proof"`
+	}
+}
+
+// seedBlob returns the blob of the evaluations sha256(seed || i) mod r, with i on 4 bytes
+func seedBlob(seed string) *Blob {
+	var blob Blob
+	var buf [4]byte
+	var e fr.Element
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		binary.BigEndian.PutUint32(buf[:], uint32(i))
+		h := sha256.Sum256(append([]byte(seed), buf[:]...))
+		e.SetBytes(h[:])
+		b := e.Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	return &blob
+}
+
+func TestKnownAnswers(t *testing.T) {
+	ctx := getTestContext(t)
+
+	content, err := os.ReadFile("testdata/known_answers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var answers knownAnswers
+	if err := json.Unmarshal(content, &answers); err != nil {
+		t.Fatal(err)
+	}
+	if answers.Tau != 42 {
+		t.Fatal("known answers don't match the SRS of the test context")
+	}
+
+	var blobs []Blob
+	var commitments []KZGCommitment
+	var blobProofs []KZGProof
+	for _, c := range answers.Cases {
+		blob := seedBlob(c.Seed)
+		var expected KZGCommitment
+		if err := decodeHex(c.Commitment, expected[:]); err != nil {
+			t.Fatal(err)
+		}
+		commitment, err := ctx.BlobToKZGCommitment(blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commitment != expected {
+			t.Fatalf("%s: wrong commitment", c.Seed)
+		}
+
+		for _, p := range c.Proofs {
+			var z, expectedY Bytes32
+			var expectedProof KZGProof
+			if err := decodeHex(p.Z, z[:]); err != nil {
+				t.Fatal(err)
+			}
+			if err := decodeHex(p.Y, expectedY[:]); err != nil {
+				t.Fatal(err)
+			}
+			if err := decodeHex(p.Proof, expectedProof[:]); err != nil {
+				t.Fatal(err)
+			}
+			proof, y, err := ctx.ComputeKZGProof(blob, z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if y != expectedY || proof != expectedProof {
+				t.Fatalf("%s: wrong proof at %s", c.Seed, p.Z)
+			}
+			if err := ctx.VerifyKZGProof(commitment, z, y, proof); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var expectedProof KZGProof
+		if err := decodeHex(c.BlobProof, expectedProof[:]); err != nil {
+			t.Fatal(err)
+		}
+		proof, err := ctx.ComputeBlobKZGProof(blob, commitment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof != expectedProof {
+			t.Fatalf("%s: wrong blob proof", c.Seed)
+		}
+		if err := ctx.VerifyBlobKZGProof(blob, commitment, proof); err != nil {
+			t.Fatal(err)
+		}
+
+		blobs = append(blobs, *blob)
+		commitments = append(commitments, commitment)
+		blobProofs = append(blobProofs, proof)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, blobProofs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewContextFromTrustedSetup(t *testing.T) {
+	ctx := getTestContext(t)
+
+	// trusted setup with the points of the test context
+	var setup trustedSetup
+	for i := range ctx.srsLagrange.G1 {
+		b := ctx.srsLagrange.G1[i].Bytes()
+		setup.G1Lagrange = append(setup.G1Lagrange, "0x"+hex.EncodeToString(b[:]))
+	}
+	for i := range testSRS.G2 {
+		b := testSRS.G2[i].Bytes()
+		setup.G2Monomial = append(setup.G2Monomial, "0x"+hex.EncodeToString(b[:]))
+	}
+	content, err := json.Marshal(&setup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromSetup, err := NewContextFromTrustedSetup(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blob, _ := randomBlob(t)
+	commitment, err := fromSetup.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := fromSetup.ComputeBlobKZGProof(blob, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobKZGProof(blob, commitment, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	// the Lagrange points must sum to the generator
+	setup.G1Lagrange[0] = setup.G1Lagrange[1]
+	content, _ = json.Marshal(&setup)
+	if _, err := NewContextFromTrustedSetup(bytes.NewReader(content)); err != ErrInvalidTrustedSetup {
+		t.Fatalf("expected ErrInvalidTrustedSetup, got %v", err)
+	}
+	setup.G1Lagrange = setup.G1Lagrange[1:]
+	content, _ = json.Marshal(&setup)
+	if _, err := NewContextFromTrustedSetup(bytes.NewReader(content)); err != ErrInvalidTrustedSetup {
+		t.Fatalf("expected ErrInvalidTrustedSetup, got %v", err)
+	}
+}
+
+// testVector is a test case of the consensus specs, the output being nil when the
+// function is expected to fail
+type testVector struct {
+	Input  map[string]interface{} `yaml:"input"`
+	Output interface{}            `yaml:"output"`
+}
+
+// runTestVectors runs the test cases of the consensus specs for the given function
+func runTestVectors(t *testing.T, handler string, run func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error)) {
+	dir, required := os.LookupEnv(specTestsEnv)
+	if !required {
+		dir = "testdata"
+	}
+	missing := func(msg string) {
+		if required {
+			t.Fatal(msg)
+		}
+		t.Skip(msg + ": conformance to the consensus specs is NOT checked (see " + specTestsEnv + ")")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, testVectorsDir, handler, "*", "*", "data.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		missing("no test vectors in " + filepath.Join(dir, testVectorsDir, handler))
+	}
+	f, err := os.Open(filepath.Join(dir, trustedSetupFile))
+	if err != nil {
+		missing("no trusted setup in " + filepath.Join(dir, trustedSetupFile))
+	}
+	defer f.Close()
+	ctx, err := NewContextFromTrustedSetup(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(filepath.Dir(file)), func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var test testVector
+			if err := yaml.Unmarshal(content, &test); err != nil {
+				t.Fatal(err)
+			}
+			output, err := run(t, ctx, test.Input)
+			if test.Output == nil {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalOutput(output, test.Output) {
+				t.Fatalf("expected %v, got %v", test.Output, output)
+			}
+		})
+	}
+}
+
+// equalOutput compares the output of a function to the one of a test vector,
+// byte slices being compared to hex strings
+func equalOutput(output, expected interface{}) bool {
+	switch o := output.(type) {
+	case bool:
+		return o == expected
+	case []byte:
+		return "0x"+hex.EncodeToString(o) == expected
+	case []interface{}:
+		e, ok := expected.([]interface{})
+		if !ok || len(e) != len(o) {
+			return false
+		}
+		for i := range o {
+			if !equalOutput(o[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+var errInvalidInput = errors.New("invalid test input")
+
+// decodeHex decodes the 0x-prefixed hex string of an input in dst, which must have
+// the same length
+func decodeHex(input interface{}, dst []byte) error {
+	s, ok := input.(string)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return errInvalidInput
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return errInvalidInput
+	}
+	copy(dst, b)
+	return nil
+}
+
+// verifyOutput maps the result of a verification function to the output of a test vector
+func verifyOutput(err error) (interface{}, error) {
+	if err == ErrVerifyProof {
+		return false, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func TestBlobToKZGCommitmentVectors(t *testing.T) {
+	runTestVectors(t, "blob_to_kzg_commitment", func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error) {
+		var blob Blob
+		if err := decodeHex(input["blob"], blob[:]); err != nil {
+			return nil, err
+		}
+		commitment, err := ctx.BlobToKZGCommitment(&blob)
+		return commitment[:], err
+	})
+}
+
+func TestComputeKZGProofVectors(t *testing.T) {
+	runTestVectors(t, "compute_kzg_proof", func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error) {
+		var blob Blob
+		var z Bytes32
+		if err := decodeHex(input["blob"], blob[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["z"], z[:]); err != nil {
+			return nil, err
+		}
+		proof, y, err := ctx.ComputeKZGProof(&blob, z)
+		return []interface{}{proof[:], y[:]}, err
+	})
+}
+
+func TestComputeBlobKZGProofVectors(t *testing.T) {
+	runTestVectors(t, "compute_blob_kzg_proof", func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error) {
+		var blob Blob
+		var commitment KZGCommitment
+		if err := decodeHex(input["blob"], blob[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["commitment"], commitment[:]); err != nil {
+			return nil, err
+		}
+		proof, err := ctx.ComputeBlobKZGProof(&blob, commitment)
+		return proof[:], err
+	})
+}
+
+func TestVerifyKZGProofVectors(t *testing.T) {
+	runTestVectors(t, "verify_kzg_proof", func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error) {
+		var commitment KZGCommitment
+		var z, y Bytes32
+		var proof KZGProof
+		if err := decodeHex(input["commitment"], commitment[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["z"], z[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["y"], y[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["proof"], proof[:]); err != nil {
+			return nil, err
+		}
+		return verifyOutput(ctx.VerifyKZGProof(commitment, z, y, proof))
+	})
+}
+
+func TestVerifyBlobKZGProofVectors(t *testing.T) {
+	runTestVectors(t, "verify_blob_kzg_proof", func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error) {
+		var blob Blob
+		var commitment KZGCommitment
+		var proof KZGProof
+		if err := decodeHex(input["blob"], blob[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["commitment"], commitment[:]); err != nil {
+			return nil, err
+		}
+		if err := decodeHex(input["proof"], proof[:]); err != nil {
+			return nil, err
+		}
+		return verifyOutput(ctx.VerifyBlobKZGProof(&blob, commitment, proof))
+	})
+}
+
+func TestVerifyBlobKZGProofBatchVectors(t *testing.T) {
+	runTestVectors(t, "verify_blob_kzg_proof_batch", func(t *testing.T, ctx *Context, input map[string]interface{}) (interface{}, error) {
+		inputs := make(map[string][]interface{})
+		for _, k := range []string{"blobs", "commitments", "proofs"} {
+			v, ok := input[k].([]interface{})
+			if !ok && input[k] != nil {
+				return nil, errInvalidInput
+			}
+			inputs[k] = v
+		}
+		blobs := make([]Blob, len(inputs["blobs"]))
+		for i := range blobs {
+			if err := decodeHex(inputs["blobs"][i], blobs[i][:]); err != nil {
+				return nil, err
+			}
+		}
+		commitments := make([]KZGCommitment, len(inputs["commitments"]))
+		for i := range commitments {
+			if err := decodeHex(inputs["commitments"][i], commitments[i][:]); err != nil {
+				return nil, err
+			}
+		}
+		proofs := make([]KZGProof, len(inputs["proofs"]))
+		for i := range proofs {
+			if err := decodeHex(inputs["proofs"][i], proofs[i][:]); err != nil {
+				return nil, err
+			}
+		}
+		return verifyOutput(ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	})
+}
+
+func BenchmarkBlobKZG(b *testing.B) {
+	srs, err := kzg.NewSRS(FieldElementsPerBlob, big.NewInt(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx, err := NewContext(srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var blob Blob
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		var e fr.Element
+		e.SetRandom()
+		b := e.Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	commitment, _ := ctx.BlobToKZGCommitment(&blob)
+	proof, _ := ctx.ComputeBlobKZGProof(&blob, commitment)
+
+	b.Run("BlobToKZGCommitment", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx.BlobToKZGCommitment(&blob)
+		}
+	})
+	b.Run("ComputeBlobKZGProof", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx.ComputeBlobKZGProof(&blob, commitment)
+		}
+	})
+	b.Run("VerifyBlobKZGProof", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx.VerifyBlobKZGProof(&blob, commitment, proof)
+		}
+	})
+}
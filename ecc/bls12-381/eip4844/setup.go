@@ -0,0 +1,98 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// trustedSetup is the JSON trusted setup of the consensus specs
+// (presets/mainnet/trusted_setup_4096.json)
+type trustedSetup struct {
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// NewContextFromTrustedSetup returns a Context from the JSON trusted setup of the
+// consensus specs, made of the points [Lᵢ(τ)]G₁ in natural order (g1_lagrange) and
+// [τⁱ]G₂ (g2_monomial), as hex strings of compressed points.
+//
+// The points are checked to be in the subgroups, and the Lagrange points to sum to
+// the generator of G₁, as ∑ᵢLᵢ = 1.
+func NewContextFromTrustedSetup(r io.Reader) (*Context, error) {
+	var setup trustedSetup
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, err
+	}
+	if len(setup.G1Lagrange) != FieldElementsPerBlob || len(setup.G2Monomial) < 2 {
+		return nil, ErrInvalidTrustedSetup
+	}
+
+	g1 := make([]bls12381.G1Affine, FieldElementsPerBlob)
+	errs := make([]error, FieldElementsPerBlob)
+	parallel.Execute(FieldElementsPerBlob, func(start, end int) {
+		for i := start; i < end; i++ {
+			errs[i] = setHexPoint(&g1[i], setup.G1Lagrange[i])
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	var g2 [2]bls12381.G2Affine
+	for i := range g2 {
+		if err := setHexPoint(&g2[i], setup.G2Monomial[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var sum bls12381.G1Jac
+	for i := range g1 {
+		sum.AddMixed(&g1[i])
+	}
+	g1Gen, _, _, _ := bls12381.Generators()
+	if !sum.Equal(&g1Gen) {
+		return nil, ErrInvalidTrustedSetup
+	}
+
+	return newContext(fft.NewDomain(FieldElementsPerBlob), g1, g2)
+}
+
+// setHexPoint sets p from the 0x-prefixed hex string of its compressed encoding
+func setHexPoint(p interface{ SetBytes([]byte) (int, error) }, s string) error {
+	if !strings.HasPrefix(s, "0x") {
+		return ErrInvalidTrustedSetup
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return err
+	}
+	n, err := p.SetBytes(b)
+	if err != nil {
+		return err
+	}
+	if n != len(b) {
+		return ErrInvalidTrustedSetup
+	}
+	return nil
+}
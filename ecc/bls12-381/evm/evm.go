@@ -0,0 +1,344 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evm implements the BLS12-381 precompiled contracts of the Ethereum
+// virtual machine, as specified in EIP-2537: G1ADD, G1MSM, G2ADD, G2MSM,
+// PAIRING, MAP_FP_TO_G1 and MAP_FP2_TO_G2.
+//
+// The functions take the raw call data and return the raw output. Unlike the
+// BN254 precompiles, inputs must have the exact expected length.
+//
+// # Encoding
+//
+//   - an element of 𝔽p is a 64-byte big-endian integer, strictly lower than p
+//     (hence with its 16 most significant bytes set to zero)
+//   - an element c0 + c1⋅u of 𝔽p² is c0 || c1 (128 bytes)
+//   - a point is x || y (128 bytes in G1, 256 bytes in G2)
+//   - the point at infinity is encoded with all coordinates set to zero
+//   - a scalar is a 32-byte big-endian integer, not required to be reduced
+//
+// Points must be on the curve. Inputs of G1MSM, G2MSM and PAIRING must
+// moreover be in the r-torsion subgroup; inputs of G1ADD and G2ADD need not.
+//
+// # See also
+//
+// https://eips.ethereum.org/EIPS/eip-2537
+package evm
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	sizeFp      = 64
+	sizeFp2     = 2 * sizeFp
+	sizeG1      = 2 * sizeFp
+	sizeG2      = 2 * sizeFp2
+	sizeScalar  = 32
+	paddingFp   = sizeFp - fp.Bytes
+	sizeG1MSM   = sizeG1 + sizeScalar
+	sizeG2MSM   = sizeG2 + sizeScalar
+	sizePairing = sizeG1 + sizeG2
+
+	// G1AddInputSize is the size of the G1ADD input
+	G1AddInputSize = 2 * sizeG1
+	// G2AddInputSize is the size of the G2ADD input
+	G2AddInputSize = 2 * sizeG2
+	// G1MSMChunkSize is the size of one (point, scalar) pair in the G1MSM input
+	G1MSMChunkSize = sizeG1MSM
+	// G2MSMChunkSize is the size of one (point, scalar) pair in the G2MSM input
+	G2MSMChunkSize = sizeG2MSM
+	// PairingChunkSize is the size of one (G1, G2) pair in the PAIRING input
+	PairingChunkSize = sizePairing
+	// MapFpToG1InputSize is the size of the MAP_FP_TO_G1 input
+	MapFpToG1InputSize = sizeFp
+	// MapFp2ToG2InputSize is the size of the MAP_FP2_TO_G2 input
+	MapFp2ToG2InputSize = sizeFp2
+)
+
+var (
+	// ErrInvalidInputLength is returned when the input doesn't have the expected length
+	ErrInvalidInputLength = errors.New("bls12-381: invalid input length")
+	// ErrInvalidFieldElement is returned when a coordinate is not a canonical encoding of an element of 𝔽p
+	ErrInvalidFieldElement = errors.New("bls12-381: invalid field element encoding")
+	// ErrNotOnCurve is returned when a point is not on the curve
+	ErrNotOnCurve = errors.New("bls12-381: point is not on curve")
+	// ErrNotInSubgroup is returned when a point is not in the r-torsion subgroup
+	ErrNotInSubgroup = errors.New("bls12-381: point is not in the correct subgroup")
+)
+
+// G1Add implements the G1ADD precompile.
+// It returns the 128-byte encoding of P + Q, where P and Q are read from input.
+func G1Add(input []byte) ([]byte, error) {
+	if len(input) != G1AddInputSize {
+		return nil, ErrInvalidInputLength
+	}
+	var p, q bls12381.G1Affine
+	if err := decodeG1(&p, input[:sizeG1], false); err != nil {
+		return nil, err
+	}
+	if err := decodeG1(&q, input[sizeG1:], false); err != nil {
+		return nil, err
+	}
+
+	var res bls12381.G1Jac
+	res.FromAffine(&p)
+	res.AddMixed(&q)
+	p.FromJacobian(&res)
+
+	return encodeG1(&p), nil
+}
+
+// G2Add implements the G2ADD precompile.
+// It returns the 256-byte encoding of P + Q, where P and Q are read from input.
+func G2Add(input []byte) ([]byte, error) {
+	if len(input) != G2AddInputSize {
+		return nil, ErrInvalidInputLength
+	}
+	var p, q bls12381.G2Affine
+	if err := decodeG2(&p, input[:sizeG2], false); err != nil {
+		return nil, err
+	}
+	if err := decodeG2(&q, input[sizeG2:], false); err != nil {
+		return nil, err
+	}
+
+	var res bls12381.G2Jac
+	res.FromAffine(&p)
+	res.AddMixed(&q)
+	p.FromJacobian(&res)
+
+	return encodeG2(&p), nil
+}
+
+// G1MSM implements the G1MSM precompile.
+// The input is a concatenation of k > 0 (point, scalar) pairs; it returns the
+// 128-byte encoding of ∑ sᵢ⋅Pᵢ.
+func G1MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%sizeG1MSM != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizeG1MSM
+
+	points := make([]bls12381.G1Affine, n)
+	scalars := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		chunk := input[i*sizeG1MSM : (i+1)*sizeG1MSM]
+		if err := decodeG1(&points[i], chunk[:sizeG1], true); err != nil {
+			return nil, err
+		}
+		// the points are in the r-torsion: reducing the scalar mod r is safe
+		scalars[i].SetBytes(chunk[sizeG1:])
+	}
+
+	var res bls12381.G1Affine
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	return encodeG1(&res), nil
+}
+
+// G2MSM implements the G2MSM precompile.
+// The input is a concatenation of k > 0 (point, scalar) pairs; it returns the
+// 256-byte encoding of ∑ sᵢ⋅Pᵢ.
+func G2MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%sizeG2MSM != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizeG2MSM
+
+	points := make([]bls12381.G2Affine, n)
+	scalars := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		chunk := input[i*sizeG2MSM : (i+1)*sizeG2MSM]
+		if err := decodeG2(&points[i], chunk[:sizeG2], true); err != nil {
+			return nil, err
+		}
+		// the points are in the r-torsion: reducing the scalar mod r is safe
+		scalars[i].SetBytes(chunk[sizeG2:])
+	}
+
+	var res bls12381.G2Affine
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	return encodeG2(&res), nil
+}
+
+// Pairing implements the PAIRING precompile.
+// The input is a concatenation of k > 0 (G1, G2) pairs; it returns 1 encoded
+// on 32 bytes if ∏ e(Pᵢ, Qᵢ) = 1, and 0 otherwise.
+func Pairing(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%sizePairing != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	n := len(input) / sizePairing
+
+	P := make([]bls12381.G1Affine, 0, n)
+	Q := make([]bls12381.G2Affine, 0, n)
+	for i := 0; i < n; i++ {
+		chunk := input[i*sizePairing : (i+1)*sizePairing]
+		var p bls12381.G1Affine
+		var q bls12381.G2Affine
+		if err := decodeG1(&p, chunk[:sizeG1], true); err != nil {
+			return nil, err
+		}
+		if err := decodeG2(&q, chunk[sizeG1:], true); err != nil {
+			return nil, err
+		}
+		// pairs with a point at infinity don't contribute to the product
+		if p.IsInfinity() || q.IsInfinity() {
+			continue
+		}
+		P = append(P, p)
+		Q = append(Q, q)
+	}
+
+	res := make([]byte, 32)
+	if len(P) == 0 {
+		res[31] = 1
+		return res, nil
+	}
+	ok, err := bls12381.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// MapFpToG1 implements the MAP_FP_TO_G1 precompile.
+// It returns the 128-byte encoding of the point obtained by the SSWU map (with
+// cofactor clearing) from the element of 𝔽p read from input.
+func MapFpToG1(input []byte) ([]byte, error) {
+	if len(input) != MapFpToG1InputSize {
+		return nil, ErrInvalidInputLength
+	}
+	var u fp.Element
+	if err := decodeFp(&u, input); err != nil {
+		return nil, err
+	}
+	res := bls12381.MapToG1(u)
+	return encodeG1(&res), nil
+}
+
+// MapFp2ToG2 implements the MAP_FP2_TO_G2 precompile.
+// It returns the 256-byte encoding of the point obtained by the SSWU map (with
+// cofactor clearing) from the element of 𝔽p² read from input.
+func MapFp2ToG2(input []byte) ([]byte, error) {
+	if len(input) != MapFp2ToG2InputSize {
+		return nil, ErrInvalidInputLength
+	}
+	var u fptower.E2
+	if err := decodeFp2(&u, input); err != nil {
+		return nil, err
+	}
+	res := bls12381.MapToG2(u)
+	return encodeG2(&res), nil
+}
+
+// decodeFp decodes a 64-byte big-endian element of 𝔽p, rejecting non-canonical values
+func decodeFp(z *fp.Element, buf []byte) error {
+	for i := 0; i < paddingFp; i++ {
+		if buf[i] != 0 {
+			return ErrInvalidFieldElement
+		}
+	}
+	if err := z.SetBytesCanonical(buf[paddingFp:sizeFp]); err != nil {
+		return ErrInvalidFieldElement
+	}
+	return nil
+}
+
+// decodeFp2 decodes c0 || c1
+func decodeFp2(z *fptower.E2, buf []byte) error {
+	if err := decodeFp(&z.A0, buf[:sizeFp]); err != nil {
+		return err
+	}
+	return decodeFp(&z.A1, buf[sizeFp:sizeFp2])
+}
+
+// decodeG1 decodes x || y into p and checks that p is on the curve and, if
+// subGroupCheck is set, in the r-torsion. (0, 0) is the point at infinity.
+func decodeG1(p *bls12381.G1Affine, buf []byte, subGroupCheck bool) error {
+	if err := decodeFp(&p.X, buf[:sizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&p.Y, buf[sizeFp:sizeG1]); err != nil {
+		return err
+	}
+	if p.IsInfinity() {
+		return nil
+	}
+	if !p.IsOnCurve() {
+		return ErrNotOnCurve
+	}
+	if subGroupCheck && !p.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// decodeG2 decodes x || y into q and checks that q is on the twist and, if
+// subGroupCheck is set, in the r-torsion. (0, 0) is the point at infinity.
+func decodeG2(q *bls12381.G2Affine, buf []byte, subGroupCheck bool) error {
+	if err := decodeFp2(&q.X, buf[:sizeFp2]); err != nil {
+		return err
+	}
+	if err := decodeFp2(&q.Y, buf[sizeFp2:sizeG2]); err != nil {
+		return err
+	}
+	if q.IsInfinity() {
+		return nil
+	}
+	if !q.IsOnCurve() {
+		return ErrNotOnCurve
+	}
+	if subGroupCheck && !q.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// encodeFp writes the 64-byte big-endian encoding of z in buf
+func encodeFp(buf []byte, z *fp.Element) {
+	b := z.Bytes()
+	copy(buf[paddingFp:sizeFp], b[:])
+}
+
+// encodeG1 returns x || y, or 128 zero bytes if p is the point at infinity
+func encodeG1(p *bls12381.G1Affine) []byte {
+	res := make([]byte, sizeG1)
+	encodeFp(res[:sizeFp], &p.X)
+	encodeFp(res[sizeFp:], &p.Y)
+	return res
+}
+
+// encodeG2 returns x.A0 || x.A1 || y.A0 || y.A1, or 256 zero bytes if p is the point at infinity
+func encodeG2(p *bls12381.G2Affine) []byte {
+	res := make([]byte, sizeG2)
+	encodeFp(res[:sizeFp], &p.X.A0)
+	encodeFp(res[sizeFp:2*sizeFp], &p.X.A1)
+	encodeFp(res[2*sizeFp:3*sizeFp], &p.Y.A0)
+	encodeFp(res[3*sizeFp:], &p.Y.A1)
+	return res
+}
@@ -0,0 +1,279 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// precompileTest is a test vector in the go-ethereum format
+// (core/vm/testdata/precompiles), which is also the one of the EIP-2537 assets
+type precompileTest struct {
+	Input, Expected string
+	ExpectedError   string
+	Name            string
+}
+
+// The official vectors of EIP-2537 (assets/eip-2537 in the EIPs repository) are
+// read from testdata/eip-2537 with their original names, or from the directory
+// set in EIP2537_TESTS. When EIP2537_TESTS is set, missing files fail the tests
+// instead of skipping them. Their expected errors are the messages of
+// go-ethereum, so only the failure is checked.
+const (
+	eip2537TestsEnv = "EIP2537_TESTS"
+	eip2537TestsDir = "testdata/eip-2537"
+)
+
+var eip2537Files = map[string]func([]byte) ([]byte, error){
+	"add_G1_bls":        G1Add,
+	"add_G2_bls":        G2Add,
+	"msm_G1_bls":        G1MSM,
+	"msm_G2_bls":        G2MSM,
+	"pairing_check_bls": Pairing,
+	"map_fp_to_G1_bls":  MapFpToG1,
+	"map_fp2_to_G2_bls": MapFp2ToG2,
+}
+
+// The vectors of testdata/extra complement the official ones. Beside the edge
+// cases built from the generators, the expected outputs of the G1ADD, G2ADD,
+// G1MSM, G2MSM and PAIRING vectors on random points were computed with
+// github.com/cloudflare/circl, and the additions of points outside of the
+// r-torsion with plain affine formulas, so that they don't depend on this package.
+// The fail- files cover each error case of EIP-2537 for each precompile.
+const extraTestsDir = "testdata/extra"
+
+func readVectors(t *testing.T, file string) []precompileTest {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []precompileTest
+	if err := json.Unmarshal(buf, &tests); err != nil {
+		t.Fatal(err)
+	}
+	return tests
+}
+
+// runVectors runs the vectors of file and of the fail- file next to it; the error
+// messages are checked if checkErrors is set
+func runVectors(t *testing.T, dir, name string, f func([]byte) ([]byte, error), checkErrors bool) {
+	for _, test := range readVectors(t, filepath.Join(dir, name+".json")) {
+		input, err := hex.DecodeString(test.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := f(input)
+		if err != nil {
+			t.Fatalf("%s: %v", test.Name, err)
+		}
+		if hex.EncodeToString(res) != test.Expected {
+			t.Fatalf("%s: expected %s, got %x", test.Name, test.Expected, res)
+		}
+	}
+
+	for _, test := range readVectors(t, filepath.Join(dir, "fail-"+name+".json")) {
+		input, err := hex.DecodeString(test.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = f(input)
+		if err == nil || (checkErrors && !strings.HasSuffix(err.Error(), test.ExpectedError)) {
+			t.Fatalf("%s: expected error %q, got %v", test.Name, test.ExpectedError, err)
+		}
+	}
+}
+
+func testVectors(t *testing.T, name string, f func([]byte) ([]byte, error)) {
+	runVectors(t, extraTestsDir, name, f, true)
+}
+
+func TestEIP2537Vectors(t *testing.T) {
+	dir, required := os.LookupEnv(eip2537TestsEnv)
+	if !required {
+		dir = eip2537TestsDir
+	}
+	for name, f := range eip2537Files {
+		t.Run(name, func(t *testing.T) {
+			for _, file := range []string{name + ".json", "fail-" + name + ".json"} {
+				if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+					if required {
+						t.Fatal(err)
+					}
+					t.Skip("no " + filepath.Join(dir, file) + ": conformance to the EIP-2537 vectors is NOT checked (see " + eip2537TestsEnv + ")")
+				}
+			}
+			runVectors(t, dir, name, f, false)
+		})
+	}
+}
+
+func TestG1Add(t *testing.T) {
+	testVectors(t, "blsG1Add", G1Add)
+}
+
+func TestG2Add(t *testing.T) {
+	testVectors(t, "blsG2Add", G2Add)
+}
+
+func TestG1MSM(t *testing.T) {
+	testVectors(t, "blsG1MSM", G1MSM)
+}
+
+func TestG2MSM(t *testing.T) {
+	testVectors(t, "blsG2MSM", G2MSM)
+}
+
+func TestPairing(t *testing.T) {
+	testVectors(t, "blsPairing", Pairing)
+}
+
+// the MAP_FP_TO_G1 and MAP_FP2_TO_G2 vectors are the encode_to_curve vectors
+// of RFC 9380 (BLS12381G1_XMD:SHA-256_SSWU_NU_ and BLS12381G2_XMD:SHA-256_SSWU_NU_)
+
+func TestMapFpToG1(t *testing.T) {
+	testVectors(t, "blsMapG1", MapFpToG1)
+}
+
+func TestMapFp2ToG2(t *testing.T) {
+	testVectors(t, "blsMapG2", MapFp2ToG2)
+}
+
+func TestMSMConsistency(t *testing.T) {
+	_, _, g1, g2 := bls12381.Generators()
+
+	const n = 5
+	var inputG1, inputG2 []byte
+	var expectedG1 bls12381.G1Jac
+	var expectedG2 bls12381.G2Jac
+	for i := 0; i < n; i++ {
+		var a, s fr.Element
+		a.SetRandom()
+		s.SetRandom()
+		var aBig, sBig big.Int
+		a.BigInt(&aBig)
+		s.BigInt(&sBig)
+
+		var p bls12381.G1Affine
+		var q bls12381.G2Affine
+		p.ScalarMultiplication(&g1, &aBig)
+		q.ScalarMultiplication(&g2, &aBig)
+
+		var scalar [sizeScalar]byte
+		sBig.FillBytes(scalar[:])
+		inputG1 = append(inputG1, encodeG1(&p)...)
+		inputG1 = append(inputG1, scalar[:]...)
+		inputG2 = append(inputG2, encodeG2(&q)...)
+		inputG2 = append(inputG2, scalar[:]...)
+
+		var pJac bls12381.G1Jac
+		var qJac bls12381.G2Jac
+		pJac.ScalarMultiplicationAffine(&p, &sBig)
+		qJac.FromAffine(&q)
+		qJac.ScalarMultiplication(&qJac, &sBig)
+		expectedG1.AddAssign(&pJac)
+		expectedG2.AddAssign(&qJac)
+	}
+
+	res, err := G1MSM(inputG1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e1 bls12381.G1Affine
+	e1.FromJacobian(&expectedG1)
+	if !bytes.Equal(res, encodeG1(&e1)) {
+		t.Fatal("G1MSM doesn't match the sum of scalar multiplications")
+	}
+
+	res, err = G2MSM(inputG2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e2 bls12381.G2Affine
+	e2.FromJacobian(&expectedG2)
+	if !bytes.Equal(res, encodeG2(&e2)) {
+		t.Fatal("G2MSM doesn't match the sum of scalar multiplications")
+	}
+}
+
+func TestSubgroupChecks(t *testing.T) {
+	_, _, g1, g2 := bls12381.Generators()
+
+	// a point of E(𝔽p) outside of G1
+	var p bls12381.G1Affine
+	var rhs, four fp.Element
+	four.SetUint64(4)
+	for {
+		p.X.SetRandom()
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &four)
+		if rhs.Legendre() == 1 {
+			break
+		}
+	}
+	p.Y.Sqrt(&rhs)
+	if !p.IsOnCurve() || p.IsInSubGroup() {
+		t.Fatal("test point should be on the curve, outside of G1")
+	}
+
+	// G1ADD doesn't check the subgroup
+	if _, err := G1Add(append(encodeG1(&p), encodeG1(&g1)...)); err != nil {
+		t.Fatal(err)
+	}
+	// G1MSM and PAIRING do
+	if _, err := G1MSM(append(encodeG1(&p), make([]byte, sizeScalar)...)); err != ErrNotInSubgroup {
+		t.Fatal("expected ErrNotInSubgroup, got", err)
+	}
+	if _, err := Pairing(append(encodeG1(&p), encodeG2(&g2)...)); err != ErrNotInSubgroup {
+		t.Fatal("expected ErrNotInSubgroup, got", err)
+	}
+
+	// a point of E'(𝔽p²) outside of G2
+	var q bls12381.G2Affine
+	var rhs2, bTwist fptower.E2
+	bTwist.A0.SetUint64(4)
+	bTwist.A1.SetUint64(4)
+	for {
+		q.X.SetRandom()
+		rhs2.Square(&q.X).Mul(&rhs2, &q.X).Add(&rhs2, &bTwist)
+		if rhs2.Legendre() == 1 {
+			break
+		}
+	}
+	q.Y.Sqrt(&rhs2)
+	if !q.IsOnCurve() || q.IsInSubGroup() {
+		t.Fatal("test point should be on the twist, outside of G2")
+	}
+
+	if _, err := G2Add(append(encodeG2(&q), encodeG2(&g2)...)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := G2MSM(append(encodeG2(&q), make([]byte, sizeScalar)...)); err != ErrNotInSubgroup {
+		t.Fatal("expected ErrNotInSubgroup, got", err)
+	}
+	if _, err := Pairing(append(encodeG1(&g1), encodeG2(&q)...)); err != ErrNotInSubgroup {
+		t.Fatal("expected ErrNotInSubgroup, got", err)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var ErrInvalidEthereumTranscript = errors.New("invalid ethereum KZG ceremony transcript")
+
+// ethereumTranscript is the JSON transcript of the Ethereum KZG ceremony,
+// holding one sub-ceremony per number of powers of τ in G₁
+type ethereumTranscript struct {
+	Transcripts []struct {
+		NumG1Powers int `json:"numG1Powers"`
+		NumG2Powers int `json:"numG2Powers"`
+		PowersOfTau struct {
+			G1Powers []string `json:"G1Powers"`
+			G2Powers []string `json:"G2Powers"`
+		} `json:"powersOfTau"`
+	} `json:"transcripts"`
+}
+
+// NewSRSFromEthereumTranscript reads the JSON transcript of the Ethereum KZG ceremony
+// and returns the SRS made of the first size powers of τ in G1 of the smallest
+// sub-ceremony holding at least size of them, or all the powers of the largest
+// sub-ceremony if size is 0.
+//
+// The points are checked to be in the subgroups, and the SRS to be made of the
+// successive powers of the τ of [τ]G₂ (see CheckSRS). The witnesses of the
+// contributions are not checked.
+func NewSRSFromEthereumTranscript(r io.Reader, size uint64) (*SRS, error) {
+	var transcript ethereumTranscript
+	if err := json.NewDecoder(r).Decode(&transcript); err != nil {
+		return nil, err
+	}
+
+	selected := -1
+	for i, t := range transcript.Transcripts {
+		n := uint64(len(t.PowersOfTau.G1Powers))
+		if n != uint64(t.NumG1Powers) || len(t.PowersOfTau.G2Powers) != t.NumG2Powers || t.NumG2Powers < 2 {
+			return nil, ErrInvalidEthereumTranscript
+		}
+		if size == 0 {
+			if selected == -1 || n > uint64(transcript.Transcripts[selected].NumG1Powers) {
+				selected = i
+			}
+		} else if n >= size && (selected == -1 || n < uint64(transcript.Transcripts[selected].NumG1Powers)) {
+			selected = i
+		}
+	}
+	if selected == -1 {
+		if len(transcript.Transcripts) == 0 {
+			return nil, ErrInvalidEthereumTranscript
+		}
+		return nil, ErrSRSTooSmall
+	}
+	powers := transcript.Transcripts[selected].PowersOfTau
+	if size == 0 {
+		size = uint64(len(powers.G1Powers))
+	}
+
+	var srs SRS
+	srs.G1 = make([]bls12381.G1Affine, size)
+	errs := make([]error, size)
+	parallel.Execute(int(size), func(start, end int) {
+		for i := start; i < end; i++ {
+			errs[i] = setEthereumPoint(&srs.G1[i], powers.G1Powers[i])
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range srs.G2 {
+		if err := setEthereumPoint(&srs.G2[i], powers.G2Powers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := CheckSRS(&srs); err != nil {
+		return nil, err
+	}
+
+	return &srs, nil
+}
+
+// setEthereumPoint decodes a point of the transcript, hex-encoded in compressed form
+func setEthereumPoint(p interface{ SetBytes([]byte) (int, error) }, s string) error {
+	if !strings.HasPrefix(s, "0x") {
+		return ErrInvalidEthereumTranscript
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return err
+	}
+	n, err := p.SetBytes(b)
+	if err != nil {
+		return err
+	}
+	if n != len(b) {
+		return ErrInvalidEthereumTranscript
+	}
+	return nil
+}
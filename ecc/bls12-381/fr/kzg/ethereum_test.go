@@ -0,0 +1,160 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeEthereumTranscript writes the JSON transcript of a ceremony with one sub-ceremony per SRS
+func writeEthereumTranscript(path string, srs ...*SRS) error {
+	var transcript ethereumTranscript
+	transcript.Transcripts = make([]struct {
+		NumG1Powers int `json:"numG1Powers"`
+		NumG2Powers int `json:"numG2Powers"`
+		PowersOfTau struct {
+			G1Powers []string `json:"G1Powers"`
+			G2Powers []string `json:"G2Powers"`
+		} `json:"powersOfTau"`
+	}, len(srs))
+	for i := range srs {
+		t := &transcript.Transcripts[i]
+		t.NumG1Powers = len(srs[i].G1)
+		t.NumG2Powers = len(srs[i].G2)
+		for j := range srs[i].G1 {
+			b := srs[i].G1[j].Bytes()
+			t.PowersOfTau.G1Powers = append(t.PowersOfTau.G1Powers, "0x"+hex.EncodeToString(b[:]))
+		}
+		for j := range srs[i].G2 {
+			b := srs[i].G2[j].Bytes()
+			t.PowersOfTau.G2Powers = append(t.PowersOfTau.G2Powers, "0x"+hex.EncodeToString(b[:]))
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(&transcript)
+}
+
+func TestEthereumTranscript(t *testing.T) {
+	small, err := NewSRS(8, big.NewInt(1789))
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := NewSRS(16, big.NewInt(1848))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "transcript.json")
+	if err := writeEthereumTranscript(path, small, large); err != nil {
+		t.Fatal(err)
+	}
+	newSRSFromTranscript := func(path string, size uint64) (*SRS, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return NewSRSFromEthereumTranscript(f, size)
+	}
+
+	// the smallest sub-ceremony with enough powers is selected
+	for _, c := range []struct {
+		size     uint64
+		expected *SRS
+		nbPowers int
+	}{
+		{0, large, 16},
+		{3, small, 3},
+		{8, small, 8},
+		{9, large, 9},
+		{16, large, 16},
+	} {
+		srs, err := newSRSFromTranscript(path, c.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(srs.G1) != c.nbPowers {
+			t.Fatalf("size %d: expected %d powers, got %d", c.size, c.nbPowers, len(srs.G1))
+		}
+		for i := range srs.G1 {
+			if !srs.G1[i].Equal(&c.expected.G1[i]) {
+				t.Fatalf("size %d: wrong power %d in G1", c.size, i)
+			}
+		}
+		if srs.G2 != c.expected.G2 {
+			t.Fatalf("size %d: wrong powers in G2", c.size)
+		}
+	}
+
+	if _, err := newSRSFromTranscript(path, 17); !errors.Is(err, ErrSRSTooSmall) {
+		t.Fatalf("expected ErrSRSTooSmall, got %v", err)
+	}
+
+	// a power of another τ
+	tampered := *large
+	tampered.G1 = append(tampered.G1[:0:0], large.G1...)
+	tampered.G1[10] = small.G1[7]
+	if err := writeEthereumTranscript(path, small, &tampered); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newSRSFromTranscript(path, 0); !errors.Is(err, ErrInconsistentSRS) {
+		t.Fatalf("expected ErrInconsistentSRS, got %v", err)
+	}
+	if _, err := newSRSFromTranscript(path, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	// invalid encoding
+	tampered.G1 = append(tampered.G1[:0:0], large.G1[:8]...)
+	if err := writeEthereumTranscript(path, &tampered); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transcript ethereumTranscript
+	if err := json.Unmarshal(content, &transcript); err != nil {
+		t.Fatal(err)
+	}
+	transcript.Transcripts[0].PowersOfTau.G1Powers[3] = "0x1234"
+	content, _ = json.Marshal(&transcript)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newSRSFromTranscript(path, 0); err == nil {
+		t.Fatal("invalid point encoding should be rejected")
+	}
+	transcript.Transcripts[0].NumG1Powers++
+	content, _ = json.Marshal(&transcript)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newSRSFromTranscript(path, 0); !errors.Is(err, ErrInvalidEthereumTranscript) {
+		t.Fatalf("expected ErrInvalidEthereumTranscript, got %v", err)
+	}
+}
@@ -17,6 +17,7 @@
 package bls12381
 
 import (
+	"crypto/subtle"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
@@ -40,6 +41,12 @@ type g1JacExtended struct {
 	X, Y, ZZ, ZZZ fp.Element
 }
 
+// g1ProjCT point in homogeneous projective coordinates (x=X/Z, y=Y/Z),
+// used with complete addition formulas for constant-time scalar multiplication
+type g1ProjCT struct {
+	X, Y, Z fp.Element
+}
+
 // -------------------------------------------------------------------------------------------------
 // Affine
 
@@ -65,6 +72,16 @@ func (p *G1Affine) ScalarMultiplication(a *G1Affine, s *big.Int) *G1Affine {
 	return p
 }
 
+// ScalarMultiplicationCT computes and returns p = a ⋅ s in constant time with respect to s.
+// see G1Jac.ScalarMultiplicationCT
+func (p *G1Affine) ScalarMultiplicationCT(a *G1Affine, s *big.Int) *G1Affine {
+	var _p G1Jac
+	_p.FromAffine(a)
+	_p.ScalarMultiplicationCT(&_p, s)
+	p.FromJacobian(&_p)
+	return p
+}
+
 // ScalarMultiplicationAffine computes and returns p = a ⋅ s
 // Takes an affine point and returns a Jacobian point (useful for KZG)
 func (p *G1Jac) ScalarMultiplicationAffine(a *G1Affine, s *big.Int) *G1Jac {
@@ -343,6 +360,45 @@ func (p *G1Jac) ScalarMultiplication(a *G1Jac, s *big.Int) *G1Jac {
 	return p.mulGLV(a, s)
 }
 
+// ScalarMultiplicationCT computes and returns p = a ⋅ s in constant time with respect to s.
+// It should be used instead of ScalarMultiplication when s is secret.
+//
+// The scalar is reduced modulo r, so a is expected to be in the r-torsion.
+// It uses a fixed 4-bits window, table lookups that read all the entries, and
+// the complete formulas of https://eprint.iacr.org/2015/1060.pdf.
+func (p *G1Jac) ScalarMultiplicationCT(a *G1Jac, s *big.Int) *G1Jac {
+	const (
+		windowSize = 4
+		tableSize  = 1 << windowSize
+	)
+
+	var e fr.Element
+	e.SetBigInt(s)
+	b := e.Bytes()
+
+	// table[i] = i ⋅ a
+	var table [tableSize]g1ProjCT
+	table[0].setInfinity()
+	table[1].fromJacobian(a)
+	for i := 2; i < tableSize; i++ {
+		table[i].add(&table[i-1], &table[1])
+	}
+
+	var res, t g1ProjCT
+	res.setInfinity()
+	for i := range b {
+		for _, w := range [2]uint8{b[i] >> windowSize, b[i] & (tableSize - 1)} {
+			for j := 0; j < windowSize; j++ {
+				res.double(&res)
+			}
+			t.lookup(&table, w)
+			res.add(&res, &t)
+		}
+	}
+
+	return p.fromProjCT(&res)
+}
+
 // String returns canonical representation of the point in affine coordinates
 func (p *G1Jac) String() string {
 	_p := G1Affine{}
@@ -826,12 +882,133 @@ func (p *g1JacExtended) doubleMixed(q *G1Affine) *g1JacExtended {
 	return p
 }
 
+// -------------------------------------------------------------------------------------------------
+// Homogenous projective, complete formulas
+
+// setInfinity sets p to O (0:1:0)
+func (p *g1ProjCT) setInfinity() *g1ProjCT {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.Z.SetZero()
+	return p
+}
+
+// fromJacobian sets p = q, p in homogenous projective, q in Jacobian
+func (p *g1ProjCT) fromJacobian(q *G1Jac) *g1ProjCT {
+	// (X, Y, Z) → (X⋅Z, Y, Z³)
+	var ZZ fp.Element
+	ZZ.Square(&q.Z)
+	p.X.Mul(&q.X, &q.Z)
+	p.Y.Set(&q.Y)
+	p.Z.Mul(&ZZ, &q.Z)
+	return p
+}
+
+// fromProjCT sets p = q, p in Jacobian, q in homogenous projective
+func (p *G1Jac) fromProjCT(q *g1ProjCT) *G1Jac {
+	if q.Z.IsZero() {
+		return p.Set(&g1Infinity)
+	}
+	// (X, Y, Z) → (X⋅Z, Y⋅Z², Z)
+	var ZZ fp.Element
+	ZZ.Square(&q.Z)
+	p.X.Mul(&q.X, &q.Z)
+	p.Y.Mul(&q.Y, &ZZ)
+	p.Z.Set(&q.Z)
+	return p
+}
+
+// lookup sets p = table[w] in constant time, reading all the entries of the table
+func (p *g1ProjCT) lookup(table *[16]g1ProjCT, w uint8) *g1ProjCT {
+	for i := range table {
+		c := subtle.ConstantTimeByteEq(w, uint8(i))
+		p.X.Select(c, &p.X, &table[i].X)
+		p.Y.Select(c, &p.Y, &table[i].Y)
+		p.Z.Select(c, &p.Z, &table[i].Z)
+	}
+	return p
+}
+
+// add sets p = a + b and returns p
+// complete addition formula for short Weierstrass curves with a=0, on groups of odd order
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 7)
+func (p *g1ProjCT) add(a, b *g1ProjCT) *g1ProjCT {
+	var b3, t0, t1, t2, t3, t4, X3, Y3, Z3 fp.Element
+	b3.Double(&bCurveCoeff).Add(&b3, &bCurveCoeff)
+
+	t0.Mul(&a.X, &b.X)
+	t1.Mul(&a.Y, &b.Y)
+	t2.Mul(&a.Z, &b.Z)
+	t3.Add(&a.X, &a.Y)
+	t4.Add(&b.X, &b.Y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&a.Y, &a.Z)
+	X3.Add(&b.Y, &b.Z)
+	t4.Mul(&t4, &X3)
+	X3.Add(&t1, &t2)
+	t4.Sub(&t4, &X3)
+	X3.Add(&a.X, &a.Z)
+	Y3.Add(&b.X, &b.Z)
+	X3.Mul(&X3, &Y3)
+	Y3.Add(&t0, &t2)
+	Y3.Sub(&X3, &Y3)
+	X3.Double(&t0)
+	t0.Add(&X3, &t0)
+	t2.Mul(&b3, &t2)
+	Z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	Y3.Mul(&b3, &Y3)
+	X3.Mul(&t4, &Y3)
+	t2.Mul(&t3, &t1)
+	X3.Sub(&t2, &X3)
+	Y3.Mul(&Y3, &t0)
+	t1.Mul(&t1, &Z3)
+	Y3.Add(&t1, &Y3)
+	t0.Mul(&t0, &t3)
+	Z3.Mul(&Z3, &t4)
+	Z3.Add(&Z3, &t0)
+
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
+// double sets p = 2⋅q and returns p
+// complete doubling formula for short Weierstrass curves with a=0, on groups of odd order
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 9)
+func (p *g1ProjCT) double(q *g1ProjCT) *g1ProjCT {
+	var b3, t0, t1, t2, X3, Y3, Z3 fp.Element
+	b3.Double(&bCurveCoeff).Add(&b3, &bCurveCoeff)
+
+	t0.Square(&q.Y)
+	Z3.Double(&t0).Double(&Z3).Double(&Z3)
+	t1.Mul(&q.Y, &q.Z)
+	t2.Square(&q.Z)
+	t2.Mul(&b3, &t2)
+	X3.Mul(&t2, &Z3)
+	Y3.Add(&t0, &t2)
+	Z3.Mul(&t1, &Z3)
+	t1.Double(&t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	Y3.Mul(&t0, &Y3)
+	Y3.Add(&X3, &Y3)
+	t1.Mul(&q.X, &q.Y)
+	X3.Mul(&t0, &t1)
+	X3.Double(&X3)
+
+	p.X, p.Y, p.Z = X3, Y3, Z3
+	return p
+}
+
 // BatchJacobianToAffineG1 converts points in Jacobian coordinates to Affine coordinates
 // performing a single field inversion (Montgomery batch inversion trick).
 func BatchJacobianToAffineG1(points []G1Jac) []G1Affine {
 	result := make([]G1Affine, len(points))
 	zeroes := make([]bool, len(points))
-	accumulator := fp.One()
+	var accumulator fp.Element
+	accumulator.SetOne()
 
 	// batch invert all points[].Z coordinates with Montgomery batch inversion trick
 	// (stores points[].Z^-1 in result[i].X to avoid allocating a slice of fr.Elements)
@@ -918,7 +1095,7 @@ func BatchScalarMultiplicationG1(base *G1Affine, scalars []fr.Element) []G1Affin
 	toReturn := make([]G1Jac, len(scalars))
 
 	// partition the scalars into digits
-	digits, _ := partitionScalars(scalars, c, runtime.NumCPU())
+	digits, _ := partitionScalars(scalars, c, uint64(nbChunks), runtime.NumCPU())
 
 	// for each digit, take value in the base table, double it c time, voilà.
 	parallel.Execute(len(scalars), func(start, end int) {
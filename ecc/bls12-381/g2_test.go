@@ -22,7 +22,7 @@ import (
 	"math/rand"
 	"testing"
 
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fptower"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/leanovate/gopter"
@@ -403,6 +403,24 @@ func TestG2AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS12-381] constant-time scalar multiplication and scalar multiplication should output the same result", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var r big.Int
+			var op1, op2, op3 G2Jac
+			var op4, op5 G2Affine
+			s.ToBigIntRegular(&r)
+			op1.ScalarMultiplication(&g2Gen, &r)
+			op2.ScalarMultiplicationCT(&g2Gen, &r)
+			op3.ScalarMultiplicationCT(&g2Gen, fr.Modulus())
+			op4.ScalarMultiplication(&g2GenAff, &r)
+			op5.ScalarMultiplicationCT(&g2GenAff, &r)
+			return op1.Equal(&op2) && op3.Equal(&g2Infinity) && op4.Equal(&op5)
+
+		},
+		genScalar,
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -506,7 +524,6 @@ func BenchmarkG2JacIsInSubGroup(b *testing.B) {
 }
 
 func BenchmarkBatchAddG2Affine(b *testing.B) {
-
 	var P, R pG2AffineC16
 	var RR ppG2AffineC16
 	ridx := make([]int, len(P))
@@ -583,6 +600,14 @@ func BenchmarkG2JacScalarMultiplication(b *testing.B) {
 		}
 	})
 
+	var ct G2Jac
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ct.ScalarMultiplicationCT(&g2Gen, &scalar)
+		}
+	})
+
 }
 
 func BenchmarkG2AffineCofactorClearing(b *testing.B) {
@@ -18,7 +18,7 @@ package bls12381
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fptower"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 	"math/rand"
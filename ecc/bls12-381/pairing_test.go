@@ -17,10 +17,12 @@
 package bls12381
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/leanovate/gopter"
@@ -157,6 +159,53 @@ func TestPairing(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestMultiExpGT(t *testing.T) {
+
+	t.Parallel()
+	const nbSamples = 73
+
+	var base GT
+	base, _ = Pair([]G1Affine{g1GenAff}, []G2Affine{g2GenAff})
+
+	x := make([]GT, nbSamples)
+	k := make([]fr.Element, nbSamples)
+	x[0] = base
+	for i := 1; i < nbSamples; i++ {
+		x[i].Mul(&x[i-1], &base)
+		k[i].SetRandom()
+	}
+	// corner cases of the signed digits
+	k[1].SetOne().Neg(&k[1])
+	k[2].SetUint64(1)
+
+	var expected, tmp GT
+	var e big.Int
+	expected.SetOne()
+	for i := range x {
+		k[i].BigInt(&e)
+		tmp.Exp(x[i], &e)
+		expected.Mul(&expected, &tmp)
+	}
+
+	for _, nbTasks := range []int{1, 5, 64, 0} {
+		var res GT
+		if _, err := res.MultiExp(x, k, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expected) {
+			t.Fatalf("MultiExp with %d tasks doesn't match the product of Exp", nbTasks)
+		}
+	}
+
+	var res GT
+	if _, err := res.MultiExp(x, k[1:], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("MultiExp should fail with inputs of different sizes")
+	}
+	if _, err := res.MultiExp(nil, nil, ecc.MultiExpConfig{}); err != nil || !res.IsOne() {
+		t.Fatal("MultiExp of no inputs should be one")
+	}
+}
+
 func TestMillerLoop(t *testing.T) {
 
 	t.Parallel()
@@ -283,6 +332,251 @@ func TestMillerLoop(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+func TestMillerLoopFixedQ(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genR1 := GenFr()
+	genR2 := GenFr()
+
+	properties.Property("[BLS12-381] MillerLoopFixedQ and MillerLoop should output the same result", prop.ForAll(
+		func(a, b fr.Element) bool {
+
+			var ag1, g1Inf G1Affine
+			var bg2, g2Inf G2Affine
+
+			var abigint, bbigint big.Int
+
+			a.ToBigIntRegular(&abigint)
+			b.ToBigIntRegular(&bbigint)
+
+			ag1.ScalarMultiplication(&g1GenAff, &abigint)
+			bg2.ScalarMultiplication(&g2GenAff, &bbigint)
+
+			tabP := []G1Affine{g1GenAff, ag1, g1Inf, ag1}
+			tabQ := []G2Affine{bg2, g2GenAff, bg2, g2Inf}
+			lines := make([]PrecomputedLines, len(tabQ))
+			for i := range tabQ {
+				lines[i] = PrecomputeLines(tabQ[i])
+			}
+
+			res1, _ := MillerLoop(tabP, tabQ)
+			res2, _ := MillerLoopFixedQ(tabP, lines)
+
+			return res1.Equal(&res2)
+		},
+		genR1,
+		genR2,
+	))
+
+	properties.Property("[BLS12-381] PairingCheckFixedQ", prop.ForAll(
+		func(a, b fr.Element) bool {
+
+			var ag1, g1Neg G1Affine
+			var bg2 G2Affine
+
+			var abigint, bbigint big.Int
+
+			a.ToBigIntRegular(&abigint)
+			b.ToBigIntRegular(&bbigint)
+
+			ag1.ScalarMultiplication(&g1GenAff, &abigint)
+			bg2.ScalarMultiplication(&g2GenAff, &bbigint)
+			g1Neg.Neg(&ag1)
+
+			lines := []PrecomputedLines{PrecomputeLines(bg2), PrecomputeLines(bg2)}
+
+			res1, _ := PairingCheckFixedQ([]G1Affine{ag1, g1Neg}, lines)
+			res2, _ := PairingCheckFixedQ([]G1Affine{ag1, g1GenAff}, lines)
+
+			return res1 && !res2
+		},
+		genR1,
+		genR2,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// serialization round trip
+	lines := PrecomputeLines(g2GenAff)
+	var buf bytes.Buffer
+	n, err := lines.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(SizeOfPrecomputedLines) || buf.Len() != SizeOfPrecomputedLines {
+		t.Fatal("invalid number of bytes written")
+	}
+	var decoded PrecomputedLines
+	if n, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(SizeOfPrecomputedLines) || decoded != lines {
+		t.Fatal("decode(encode(lines)) failed")
+	}
+	if _, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:SizeOfPrecomputedLines-1])); err == nil {
+		t.Fatal("short buffer should be rejected")
+	}
+	encoded := buf.Bytes()
+	for i := 0; i < fp.Bytes; i++ {
+		encoded[i] = 0xff
+	}
+	if _, err = decoded.ReadFrom(bytes.NewReader(encoded)); err == nil {
+		t.Fatal("non canonical coordinates should be rejected")
+	}
+}
+
+func TestFinalExponentiationWitness(t *testing.T) {
+
+	t.Parallel()
+	nbTests := 3
+	if testing.Short() {
+		nbTests = 1
+	}
+
+	for i := 0; i < nbTests; i++ {
+		var a, b fr.Element
+		a.SetRandom()
+		b.SetRandom()
+
+		var abigint, bbigint big.Int
+		a.BigInt(&abigint)
+		b.BigInt(&bbigint)
+
+		// e(aP, Q) ⋅ e(-P, aQ) ⋅ e(bP, ∞) = 1
+		var ag1, bg1, g1Neg G1Affine
+		var ag2, g2Inf G2Affine
+		ag1.ScalarMultiplication(&g1GenAff, &abigint)
+		bg1.ScalarMultiplication(&g1GenAff, &bbigint)
+		g1Neg.Neg(&g1GenAff)
+		ag2.ScalarMultiplication(&g2GenAff, &abigint)
+
+		P := []G1Affine{ag1, g1Neg, bg1}
+		Q := []G2Affine{g2GenAff, ag2, g2Inf}
+		lines := make([]PrecomputedLines, len(Q))
+		for j := range Q {
+			lines[j] = PrecomputeLines(Q[j])
+		}
+
+		f, err := MillerLoop(P, Q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, w, err := FinalExponentiationWitness(&f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !CheckFinalExponentiationWitness(&f, &c, &w) {
+			t.Fatal("residue witness should be valid")
+		}
+		if ok, err := CheckPairingHints(P, Q, lines, &c, &w); err != nil || !ok {
+			t.Fatal("pairing hints should be valid")
+		}
+
+		// invalid witnesses
+		var wrong GT
+		wrong.Mul(&c, &c)
+		if CheckFinalExponentiationWitness(&f, &wrong, &w) {
+			t.Fatal("invalid c should be rejected")
+		}
+		wrong.Mul(&w, &f)
+		if CheckFinalExponentiationWitness(&f, &c, &wrong) {
+			t.Fatal("invalid w should be rejected")
+		}
+		lines[0], lines[1] = lines[1], lines[0]
+		if ok, _ := CheckPairingHints(P, Q, lines, &c, &w); ok {
+			t.Fatal("invalid lines should be rejected")
+		}
+
+		// a pairing product ≠ 1 has no residue witness
+		P[2] = ag1
+		Q[2] = ag2
+		f, _ = MillerLoop(P, Q)
+		if _, _, err = FinalExponentiationWitness(&f); err == nil {
+			t.Fatal("FinalExponentiationWitness should fail when the pairing product is not one")
+		}
+	}
+}
+
+func TestBatchPairingCheck(t *testing.T) {
+
+	t.Parallel()
+	const nbEquations = 9
+
+	// equation i is e(aᵢ⋅g1, b⋅g2) ⋅ e(-aᵢb⋅g1, g2) ⋅ e(g1, cᵢ⋅g2) ⋅ e(-cᵢ⋅g1, g2) == 1,
+	// so that the equations share the G2 points b⋅g2 and g2
+	var b, a, c, ab fr.Element
+	var bBig, aBig, cBig, abBig big.Int
+	b.SetRandom()
+	b.BigInt(&bBig)
+	var bg2 G2Affine
+	bg2.ScalarMultiplication(&g2GenAff, &bBig)
+
+	P := make([][]G1Affine, nbEquations)
+	Q := make([][]G2Affine, nbEquations)
+	for i := range P {
+		a.SetRandom()
+		c.SetRandom()
+		ab.Mul(&a, &b).Neg(&ab)
+		c.BigInt(&cBig)
+		a.BigInt(&aBig)
+		ab.BigInt(&abBig)
+		P[i] = make([]G1Affine, 4)
+		Q[i] = make([]G2Affine, 4)
+		P[i][0].ScalarMultiplication(&g1GenAff, &aBig)
+		P[i][1].ScalarMultiplication(&g1GenAff, &abBig)
+		P[i][2] = g1GenAff
+		P[i][3].ScalarMultiplication(&g1GenAff, &cBig)
+		P[i][3].Neg(&P[i][3])
+		Q[i][0] = bg2
+		Q[i][1] = g2GenAff
+		Q[i][2].ScalarMultiplication(&g2GenAff, &cBig)
+		Q[i][3] = g2GenAff
+	}
+	// an equation with points at infinity
+	P[4][0].X.SetZero()
+	P[4][0].Y.SetZero()
+	P[4][1].X.SetZero()
+	P[4][1].Y.SetZero()
+
+	failed, err := BatchPairingCheck(P, Q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("valid equations reported as failing: %v", failed)
+	}
+
+	// break some equations
+	for _, i := range []int{2, 7, 8} {
+		P[i][2].Add(&P[i][2], &P[i][2])
+	}
+	failed, err = BatchPairingCheck(P, Q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(failed) != fmt.Sprint([]int{2, 7, 8}) {
+		t.Fatalf("expected failing equations [2 7 8], got %v", failed)
+	}
+
+	if _, err := BatchPairingCheck(P, Q[1:]); err == nil {
+		t.Fatal("BatchPairingCheck should fail with inputs of different sizes")
+	}
+	if _, err := BatchPairingCheck([][]G1Affine{P[0][1:]}, Q[:1]); err == nil {
+		t.Fatal("BatchPairingCheck should fail with an equation of inconsistent size")
+	}
+	if failed, err := BatchPairingCheck(nil, nil); err != nil || len(failed) != 0 {
+		t.Fatal("BatchPairingCheck of no equations should succeed")
+	}
+}
 
 // ------------------------------------------------------------
 // benches
@@ -314,6 +608,30 @@ func BenchmarkMillerLoop(b *testing.B) {
 		MillerLoop([]G1Affine{g1GenAff}, []G2Affine{g2GenAff})
 	}
 }
+func BenchmarkMillerLoopFixedQ(b *testing.B) {
+
+	var g1GenAff G1Affine
+	var g2GenAff G2Affine
+
+	g1GenAff.FromJacobian(&g1Gen)
+	g2GenAff.FromJacobian(&g2Gen)
+	lines := []PrecomputedLines{PrecomputeLines(g2GenAff)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MillerLoopFixedQ([]G1Affine{g1GenAff}, lines)
+	}
+}
+
+func BenchmarkFinalExponentiationWitness(b *testing.B) {
+	var g1Neg G1Affine
+	g1Neg.Neg(&g1GenAff)
+	f, _ := MillerLoop([]G1Affine{g1GenAff, g1Neg}, []G2Affine{g2GenAff, g2GenAff})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FinalExponentiationWitness(&f)
+	}
+}
 
 func BenchmarkFinalExponentiation(b *testing.B) {
 
@@ -379,6 +697,77 @@ func BenchmarkMultiPair(b *testing.B) {
 	}
 }
 
+func BenchmarkBatchPairingCheck(b *testing.B) {
+
+	// BLS signature like equations e(σᵢ, g2) ⋅ e(-hᵢ, pk) == 1 sharing the G2 points
+	const nbEquations = 64
+	var sk fr.Element
+	var skBig big.Int
+	sk.SetRandom()
+	sk.BigInt(&skBig)
+	var pk G2Affine
+	pk.ScalarMultiplication(&g2GenAff, &skBig)
+
+	P := make([][]G1Affine, nbEquations)
+	Q := make([][]G2Affine, nbEquations)
+	var h fr.Element
+	var hBig big.Int
+	for i := range P {
+		h.SetRandom()
+		h.BigInt(&hBig)
+		P[i] = make([]G1Affine, 2)
+		P[i][1].ScalarMultiplication(&g1GenAff, &hBig)
+		P[i][0].ScalarMultiplication(&P[i][1], &skBig)
+		P[i][1].Neg(&P[i][1])
+		Q[i] = []G2Affine{g2GenAff, pk}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchPairingCheck(P, Q)
+	}
+}
+
+func BenchmarkMultiExpGT(b *testing.B) {
+
+	const nbSamples = 1 << 10
+	var base GT
+	base.SetRandom()
+	base = FinalExponentiation(&base)
+
+	x := make([]GT, nbSamples)
+	k := make([]fr.Element, nbSamples)
+	x[0] = base
+	for i := 1; i < nbSamples; i++ {
+		x[i].Mul(&x[i-1], &base)
+		k[i].SetRandom()
+	}
+
+	for i := 5; i <= 10; i += 5 {
+		b.Run(fmt.Sprintf("%d elements", 1<<i), func(b *testing.B) {
+			var res GT
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				res.MultiExp(x[:1<<i], k[:1<<i], ecc.MultiExpConfig{})
+			}
+		})
+	}
+
+	b.Run(fmt.Sprintf("%d ExpGLV", nbSamples), func(b *testing.B) {
+		var res, tmp GT
+		var e big.Int
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			res.SetOne()
+			for i := range x {
+				k[i].BigInt(&e)
+				tmp.ExpGLV(x[i], &e)
+				res.Mul(&res, &tmp)
+			}
+		}
+	})
+}
+
 func BenchmarkExpGT(b *testing.B) {
 
 	var a GT
@@ -0,0 +1,23 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fptower provides the extension field tower of bls24-315,
+// E2 = Fp[u]/(u²-13), E4 = E2[v]/(v²-u), E12 = E4[w]/(w³-v) and E24 = E12[i]/(i²-w).
+//
+// E24 is the field of the target group of the pairing: bls24-315.GT is an alias of
+// E24. Besides the field arithmetic, E24 implements the operations used by the
+// pairing and by protocols verifying it, such as the Frobenius maps, the
+// exponentiation by the seed of the curve (Expt) and the compressed
+// representations of the cyclotomic subgroup.
+package fptower
@@ -226,6 +226,14 @@ func (z *E2) Sqrt(x *E2) *E2 {
 	return z
 }
 
+// Select is a constant-time conditional move.
+// If cond=0, z = caseZ. Else z = caseNz
+func (z *E2) Select(cond int, caseZ *E2, caseNz *E2) *E2 {
+	z.A0.Select(cond, &caseZ.A0, &caseNz.A0)
+	z.A1.Select(cond, &caseZ.A1, &caseNz.A1)
+	return z
+}
+
 func (z *E2) Div(x *E2, y *E2) *E2 {
 	var r E2
 	r.Inverse(y).Mul(x, &r)
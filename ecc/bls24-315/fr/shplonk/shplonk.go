@@ -0,0 +1,382 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package shplonk
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidNbDigests      = errors.New("number of digests, point sets and polynomials or claimed values don't match")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrInvalidPointSet       = errors.New("point sets must be non empty and made of distinct points")
+	ErrVerifyOpeningProof    = errors.New("can't verify batch opening proof")
+)
+
+// OpeningProof proof of the openings of polynomials fᵢ on sets of points Sᵢ.
+//
+// With T = ∪ᵢSᵢ, Z_S the vanishing polynomial of a set S and rᵢ the polynomial of degree
+// less than |Sᵢ| interpolating fᵢ on Sᵢ, the proof holds the commitments to
+//
+//	h = ∑ᵢγⁱ(fᵢ-rᵢ)/Z_{Sᵢ}
+//	L/(X-z), where L = ∑ᵢγⁱZ_{T\Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)h
+//
+// for the challenges γ and z.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// W commitment to h
+	W bls24315.G1Affine
+
+	// WPrime commitment to L/(X-z)
+	WPrime bls24315.G1Affine
+
+	// ClaimedValues ClaimedValues[i][j] = fᵢ(points[i][j])
+	ClaimedValues [][]fr.Element
+}
+
+// BatchOpen opens each polynomial polynomials[i] on the set of points points[i].
+//
+// * digests are the commitments to the polynomials, bound to the challenges
+// * hf is the hash function used to derive the challenges γ and z with a fiatshamir.Transcript
+// * dataTranscript are data of the protocol bound to γ before the digests, the points and the claimed values
+func BatchOpen(polynomials [][]fr.Element, digests []kzg.Digest, points [][]fr.Element, hf hash.Hash, srs *kzg.SRS, dataTranscript ...[]byte) (OpeningProof, error) {
+
+	if len(polynomials) != len(digests) || len(polynomials) != len(points) {
+		return OpeningProof{}, ErrInvalidNbDigests
+	}
+	largestPoly := 0
+	for i := range polynomials {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(srs.G1) {
+			return OpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
+	}
+	t, err := buildT(points)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	var res OpeningProof
+	res.ClaimedValues = make([][]fr.Element, len(polynomials))
+	for i := range polynomials {
+		res.ClaimedValues[i] = make([]fr.Element, len(points[i]))
+		for j := range points[i] {
+			res.ClaimedValues[i][j] = eval(polynomials[i], points[i][j])
+		}
+	}
+
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveGamma(&fs, digests, points, res.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	// h = ∑ᵢγⁱ(fᵢ-rᵢ)/Z_{Sᵢ}, where (fᵢ-rᵢ)/Z_{Sᵢ} is the quotient of fᵢ by Z_{Sᵢ}
+	h := make([]fr.Element, largestPoly)
+	var gammaI, tmp fr.Element
+	gammaI.SetOne()
+	for i := range polynomials {
+		q := make([]fr.Element, len(polynomials[i]))
+		copy(q, polynomials[i])
+		for j := 0; j < len(points[i]) && len(q) > 0; j++ {
+			q = divideByXMinusA(q, points[i][j])
+		}
+		for j := range q {
+			tmp.Mul(&q[j], &gammaI)
+			h[j].Add(&h[j], &tmp)
+		}
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	res.W, err = kzg.Commit(h, srs)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	z, err := deriveZ(&fs, &res.W)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	// L = ∑ᵢγⁱZ_{T\Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)h
+	coeffs, zT, rz := foldingCoefficients(t, points, res.ClaimedValues, gamma, z)
+	// (one more coefficient so that the quotient by X-z is not empty)
+	l := make([]fr.Element, largestPoly+1)
+	for i := range polynomials {
+		for j := range polynomials[i] {
+			tmp.Mul(&polynomials[i][j], &coeffs[i])
+			l[j].Add(&l[j], &tmp)
+		}
+	}
+	l[0].Sub(&l[0], &rz)
+	for j := range h {
+		tmp.Mul(&h[j], &zT)
+		l[j].Sub(&l[j], &tmp)
+	}
+
+	// L(z) = 0
+	res.WPrime, err = kzg.Commit(divideByXMinusA(l, z), srs)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerify verifies a batch opening proof of the polynomials committed in digests,
+// digests[i] being opened on the set points[i].
+//
+// The challenges are derived as in BatchOpen from hf and dataTranscript, and the proof
+// is accepted if
+//
+//	e(F + [z]W', G₂) == e(W', [τ]G₂)
+//
+// where F = ∑ᵢγⁱZ_{T\Sᵢ}(z)(digests[i] - [rᵢ(z)]G₁) - Z_T(z)W.
+func BatchVerify(proof *OpeningProof, digests []kzg.Digest, points [][]fr.Element, hf hash.Hash, srs *kzg.SRS, dataTranscript ...[]byte) error {
+
+	if len(digests) != len(points) || len(digests) != len(proof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	for i := range points {
+		if len(points[i]) != len(proof.ClaimedValues[i]) {
+			return ErrInvalidNbDigests
+		}
+	}
+	t, err := buildT(points)
+	if err != nil {
+		return err
+	}
+
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveGamma(&fs, digests, points, proof.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	z, err := deriveZ(&fs, &proof.W)
+	if err != nil {
+		return err
+	}
+	coeffs, zT, rz := foldingCoefficients(t, points, proof.ClaimedValues, gamma, z)
+
+	// F + [z]W' = ∑ᵢcᵢdigests[i] - [∑ᵢcᵢrᵢ(z)]G₁ - Z_T(z)W + [z]W'
+	bases := make([]bls24315.G1Affine, 0, len(digests)+3)
+	bases = append(bases, digests...)
+	bases = append(bases, srs.G1[0], proof.W, proof.WPrime)
+	scalars := make([]fr.Element, 0, len(digests)+3)
+	scalars = append(scalars, coeffs...)
+	var negRz, negZT fr.Element
+	negRz.Neg(&rz)
+	negZT.Neg(&zT)
+	scalars = append(scalars, negRz, negZT, z)
+
+	var f bls24315.G1Affine
+	if _, err := f.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	var negWPrime bls24315.G1Affine
+	negWPrime.Neg(&proof.WPrime)
+	check, err := bls24315.PairingCheck(
+		[]bls24315.G1Affine{f, negWPrime},
+		[]bls24315.G2Affine{srs.G2[0], srs.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// buildT returns T = ∪ᵢSᵢ, and checks that the Sᵢ are non empty sets of distinct points
+func buildT(points [][]fr.Element) ([]fr.Element, error) {
+	var t []fr.Element
+	inT := make(map[fr.Element]struct{})
+	for i := range points {
+		if len(points[i]) == 0 {
+			return nil, ErrInvalidPointSet
+		}
+		inS := make(map[fr.Element]struct{}, len(points[i]))
+		for _, x := range points[i] {
+			if _, ok := inS[x]; ok {
+				return nil, ErrInvalidPointSet
+			}
+			inS[x] = struct{}{}
+			if _, ok := inT[x]; !ok {
+				inT[x] = struct{}{}
+				t = append(t, x)
+			}
+		}
+	}
+	return t, nil
+}
+
+// foldingCoefficients returns cᵢ = γⁱZ_{T\Sᵢ}(z), Z_T(z) and ∑ᵢcᵢrᵢ(z), where rᵢ interpolates
+// the claimed values of the i-th polynomial on Sᵢ.
+func foldingCoefficients(t []fr.Element, points, claimedValues [][]fr.Element, gamma, z fr.Element) ([]fr.Element, fr.Element, fr.Element) {
+
+	// z - x for x ∈ T
+	zMinusT := make(map[fr.Element]fr.Element, len(t))
+	var zT fr.Element
+	zT.SetOne()
+	for _, x := range t {
+		var d fr.Element
+		d.Sub(&z, &x)
+		zMinusT[x] = d
+		zT.Mul(&zT, &d)
+	}
+
+	coeffs := make([]fr.Element, len(points))
+	var rz, gammaI, tmp fr.Element
+	gammaI.SetOne()
+	for i := range points {
+		// Z_{T\Sᵢ}(z)
+		inS := make(map[fr.Element]struct{}, len(points[i]))
+		for _, x := range points[i] {
+			inS[x] = struct{}{}
+		}
+		coeffs[i].Set(&gammaI)
+		for _, x := range t {
+			if _, ok := inS[x]; !ok {
+				d := zMinusT[x]
+				coeffs[i].Mul(&coeffs[i], &d)
+			}
+		}
+
+		r := interpolateAt(points[i], claimedValues[i], z)
+		tmp.Mul(&r, &coeffs[i])
+		rz.Add(&rz, &tmp)
+
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	return coeffs, zT, rz
+}
+
+// interpolateAt returns r(z), where r is the polynomial of degree less than len(x) such that
+// r(x[i]) = y[i], using the Lagrange formula r(z) = ∑ᵢyᵢ∏_{j≠i}(z-xⱼ)/(xᵢ-xⱼ)
+func interpolateAt(x, y []fr.Element, z fr.Element) fr.Element {
+	n := len(x)
+	denominators := make([]fr.Element, n)
+	numerators := make([]fr.Element, n)
+	var d fr.Element
+	for i := 0; i < n; i++ {
+		denominators[i].SetOne()
+		numerators[i].Set(&y[i])
+		for j := 0; j < n; j++ {
+			if j == i {
+				continue
+			}
+			d.Sub(&x[i], &x[j])
+			denominators[i].Mul(&denominators[i], &d)
+			d.Sub(&z, &x[j])
+			numerators[i].Mul(&numerators[i], &d)
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res fr.Element
+	for i := 0; i < n; i++ {
+		d.Mul(&numerators[i], &denominators[i])
+		res.Add(&res, &d)
+	}
+	return res
+}
+
+// deriveGamma derives γ from the data of the protocol, the digests, the points and the claimed values
+func deriveGamma(fs *fiatshamir.Transcript, digests []kzg.Digest, points, claimedValues [][]fr.Element, dataTranscript ...[]byte) (fr.Element, error) {
+	for i := range dataTranscript {
+		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range points {
+		for j := range points[i] {
+			if err := fs.Bind("gamma", points[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+	for i := range claimedValues {
+		for j := range claimedValues[i] {
+			if err := fs.Bind("gamma", claimedValues[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
+
+// deriveZ derives z from γ and the commitment W
+func deriveZ(fs *fiatshamir.Transcript, w *bls24315.G1Affine) (fr.Element, error) {
+	if err := fs.Bind("z", w.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	zByte, err := fs.ComputeChallenge("z")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var z fr.Element
+	z.SetBytes(zByte)
+
+	return z, nil
+}
+
+// eval returns p(point) where p is interpreted as a polynomial
+// ∑_{i<len(p)}p[i]Xⁱ
+func eval(p []fr.Element, point fr.Element) fr.Element {
+	var res fr.Element
+	n := len(p)
+	res.Set(&p[n-1])
+	for i := n - 2; i >= 0; i-- {
+		res.Mul(&res, &point).Add(&res, &p[i])
+	}
+	return res
+}
+
+// divideByXMinusA returns the quotient of the division of f by X-a, in canonical basis,
+// the remainder f(a) being dropped. f memory is re-used for the result.
+func divideByXMinusA(f []fr.Element, a fr.Element) []fr.Element {
+	var t fr.Element
+	for i := len(f) - 2; i >= 0; i-- {
+		t.Mul(&f[i+1], &a)
+		f[i].Add(&f[i], &t)
+	}
+	return f[1:]
+}
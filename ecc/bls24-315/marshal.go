@@ -24,8 +24,8 @@ import (
 	"sync/atomic"
 
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fptower"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
-	"github.com/consensys/gnark-crypto/ecc/bls24-315/internal/fptower"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 )
 
@@ -50,6 +50,9 @@ const (
 // SizeOfGT represents the size in bytes that a GT element need in binary form
 const SizeOfGT = fptower.SizeOfGT
 
+// SizeOfGTCompressed represents the size in bytes that a compressed GT element need in binary form
+const SizeOfGTCompressed = fptower.SizeOfGTCompressed
+
 // Encoder writes bls24-315 object values to an output stream
 type Encoder struct {
 	w   io.Writer
@@ -77,7 +80,7 @@ func NewDecoder(r io.Reader, options ...func(*Decoder)) *Decoder {
 }
 
 // Decode reads the binary encoding of v from the stream
-// type must be *uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *[]G1Affine or *[]G2Affine
+// type must be *uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *GT, *[]G1Affine, *[]G2Affine or *[]GT
 func (dec *Decoder) Decode(v interface{}) (err error) {
 	rv := reflect.ValueOf(v)
 	if v == nil || rv.Kind() != reflect.Ptr || rv.IsNil() || !rv.Elem().CanSet() {
@@ -192,65 +195,34 @@ func (dec *Decoder) Decode(v interface{}) (err error) {
 		}
 		_, err = t.setBytes(buf[:nbBytes], dec.subGroupCheck)
 		return
-	case *[]G1Affine:
+	case *GT:
+		err = dec.readGT(t)
+		return
+	case *[]GT:
 		var sliceLen uint32
 		sliceLen, err = dec.readUint32()
 		if err != nil {
 			return
 		}
 		if len(*t) != int(sliceLen) {
-			*t = make([]G1Affine, sliceLen)
+			*t = make([]GT, sliceLen)
 		}
-		compressed := make([]bool, sliceLen)
-		for i := 0; i < len(*t); i++ {
-
-			// we start by reading compressed point size, if metadata tells us it is uncompressed, we read more.
-			read, err = io.ReadFull(dec.r, buf[:SizeOfG1AffineCompressed])
-			dec.n += int64(read)
-			if err != nil {
+		for i := range *t {
+			if err = dec.readGT(&(*t)[i]); err != nil {
 				return
 			}
-			nbBytes := SizeOfG1AffineCompressed
-			// most significant byte contains metadata
-			if !isCompressed(buf[0]) {
-				nbBytes = SizeOfG1AffineUncompressed
-				// we read more.
-				read, err = io.ReadFull(dec.r, buf[SizeOfG1AffineCompressed:SizeOfG1AffineUncompressed])
-				dec.n += int64(read)
-				if err != nil {
-					return
-				}
-				_, err = (*t)[i].setBytes(buf[:nbBytes], false)
-				if err != nil {
-					return
-				}
-			} else {
-				var r bool
-				if r, err = ((*t)[i].unsafeSetCompressedBytes(buf[:nbBytes])); err != nil {
-					return
-				}
-				compressed[i] = !r
-			}
 		}
-		var nbErrs uint64
-		parallel.Execute(len(compressed), func(start, end int) {
-			for i := start; i < end; i++ {
-				if compressed[i] {
-					if err := (*t)[i].unsafeComputeY(dec.subGroupCheck); err != nil {
-						atomic.AddUint64(&nbErrs, 1)
-					}
-				} else if dec.subGroupCheck {
-					if !(*t)[i].IsInSubGroup() {
-						atomic.AddUint64(&nbErrs, 1)
-					}
-				}
-			}
-		})
-		if nbErrs != 0 {
-			return errors.New("point decompression failed")
+		return
+	case *[]G1Affine:
+		var sliceLen uint32
+		sliceLen, err = dec.readUint32()
+		if err != nil {
+			return
 		}
-
-		return nil
+		if len(*t) != int(sliceLen) {
+			*t = make([]G1Affine, sliceLen)
+		}
+		return dec.readG1AffineSlice(*t)
 	case *[]G2Affine:
 		var sliceLen uint32
 		sliceLen, err = dec.readUint32()
@@ -260,67 +232,130 @@ func (dec *Decoder) Decode(v interface{}) (err error) {
 		if len(*t) != int(sliceLen) {
 			*t = make([]G2Affine, sliceLen)
 		}
-		compressed := make([]bool, sliceLen)
-		for i := 0; i < len(*t); i++ {
+		return dec.readG2AffineSlice(*t)
+	default:
+		n := binary.Size(t)
+		if n == -1 {
+			return errors.New("bls24-315 encoder: unsupported type")
+		}
+		err = binary.Read(dec.r, binary.BigEndian, t)
+		if err == nil {
+			dec.n += int64(n)
+		}
+		return
+	}
+}
+
+// readG1AffineSlice decodes len(points) points, the length prefix being already read
+func (dec *Decoder) readG1AffineSlice(points []G1Affine) (err error) {
+	var buf [SizeOfG1AffineUncompressed]byte
+	var read int
+	compressed := make([]bool, len(points))
+	for i := 0; i < len(points); i++ {
 
-			// we start by reading compressed point size, if metadata tells us it is uncompressed, we read more.
-			read, err = io.ReadFull(dec.r, buf[:SizeOfG2AffineCompressed])
+		// we start by reading compressed point size, if metadata tells us it is uncompressed, we read more.
+		read, err = io.ReadFull(dec.r, buf[:SizeOfG1AffineCompressed])
+		dec.n += int64(read)
+		if err != nil {
+			return
+		}
+		nbBytes := SizeOfG1AffineCompressed
+		// most significant byte contains metadata
+		if !isCompressed(buf[0]) {
+			nbBytes = SizeOfG1AffineUncompressed
+			// we read more.
+			read, err = io.ReadFull(dec.r, buf[SizeOfG1AffineCompressed:SizeOfG1AffineUncompressed])
 			dec.n += int64(read)
 			if err != nil {
 				return
 			}
-			nbBytes := SizeOfG2AffineCompressed
-			// most significant byte contains metadata
-			if !isCompressed(buf[0]) {
-				nbBytes = SizeOfG2AffineUncompressed
-				// we read more.
-				read, err = io.ReadFull(dec.r, buf[SizeOfG2AffineCompressed:SizeOfG2AffineUncompressed])
-				dec.n += int64(read)
-				if err != nil {
-					return
-				}
-				_, err = (*t)[i].setBytes(buf[:nbBytes], false)
-				if err != nil {
-					return
-				}
-			} else {
-				var r bool
-				if r, err = ((*t)[i].unsafeSetCompressedBytes(buf[:nbBytes])); err != nil {
-					return
-				}
-				compressed[i] = !r
+			_, err = points[i].setBytes(buf[:nbBytes], false)
+			if err != nil {
+				return
+			}
+		} else {
+			var r bool
+			if r, err = (points[i].unsafeSetCompressedBytes(buf[:nbBytes])); err != nil {
+				return
 			}
+			compressed[i] = !r
 		}
-		var nbErrs uint64
-		parallel.Execute(len(compressed), func(start, end int) {
-			for i := start; i < end; i++ {
-				if compressed[i] {
-					if err := (*t)[i].unsafeComputeY(dec.subGroupCheck); err != nil {
-						atomic.AddUint64(&nbErrs, 1)
-					}
-				} else if dec.subGroupCheck {
-					if !(*t)[i].IsInSubGroup() {
-						atomic.AddUint64(&nbErrs, 1)
-					}
+	}
+	var nbErrs uint64
+	parallel.Execute(len(compressed), func(start, end int) {
+		for i := start; i < end; i++ {
+			if compressed[i] {
+				if err := points[i].unsafeComputeY(dec.subGroupCheck); err != nil {
+					atomic.AddUint64(&nbErrs, 1)
+				}
+			} else if dec.subGroupCheck {
+				if !points[i].IsInSubGroup() {
+					atomic.AddUint64(&nbErrs, 1)
 				}
 			}
-		})
-		if nbErrs != 0 {
-			return errors.New("point decompression failed")
 		}
+	})
+	if nbErrs != 0 {
+		return errors.New("point decompression failed")
+	}
 
-		return nil
-	default:
-		n := binary.Size(t)
-		if n == -1 {
-			return errors.New("bls24-315 encoder: unsupported type")
+	return nil
+}
+
+// readG2AffineSlice decodes len(points) points, the length prefix being already read
+func (dec *Decoder) readG2AffineSlice(points []G2Affine) (err error) {
+	var buf [SizeOfG2AffineUncompressed]byte
+	var read int
+	compressed := make([]bool, len(points))
+	for i := 0; i < len(points); i++ {
+
+		// we start by reading compressed point size, if metadata tells us it is uncompressed, we read more.
+		read, err = io.ReadFull(dec.r, buf[:SizeOfG2AffineCompressed])
+		dec.n += int64(read)
+		if err != nil {
+			return
 		}
-		err = binary.Read(dec.r, binary.BigEndian, t)
-		if err == nil {
-			dec.n += int64(n)
+		nbBytes := SizeOfG2AffineCompressed
+		// most significant byte contains metadata
+		if !isCompressed(buf[0]) {
+			nbBytes = SizeOfG2AffineUncompressed
+			// we read more.
+			read, err = io.ReadFull(dec.r, buf[SizeOfG2AffineCompressed:SizeOfG2AffineUncompressed])
+			dec.n += int64(read)
+			if err != nil {
+				return
+			}
+			_, err = points[i].setBytes(buf[:nbBytes], false)
+			if err != nil {
+				return
+			}
+		} else {
+			var r bool
+			if r, err = (points[i].unsafeSetCompressedBytes(buf[:nbBytes])); err != nil {
+				return
+			}
+			compressed[i] = !r
 		}
-		return
 	}
+	var nbErrs uint64
+	parallel.Execute(len(compressed), func(start, end int) {
+		for i := start; i < end; i++ {
+			if compressed[i] {
+				if err := points[i].unsafeComputeY(dec.subGroupCheck); err != nil {
+					atomic.AddUint64(&nbErrs, 1)
+				}
+			} else if dec.subGroupCheck {
+				if !points[i].IsInSubGroup() {
+					atomic.AddUint64(&nbErrs, 1)
+				}
+			}
+		}
+	})
+	if nbErrs != 0 {
+		return errors.New("point decompression failed")
+	}
+
+	return nil
 }
 
 // BytesRead return total bytes read from reader
@@ -340,6 +375,37 @@ func (dec *Decoder) readUint32() (r uint32, err error) {
 	return
 }
 
+// readGT reads a GT element, in compressed or raw form
+func (dec *Decoder) readGT(z *GT) error {
+	// we start by reading the compressed size, if metadata tells us it is raw, we read more.
+	var buf [SizeOfGT]byte
+	read, err := io.ReadFull(dec.r, buf[:SizeOfGTCompressed])
+	dec.n += int64(read)
+	if err != nil {
+		return err
+	}
+	// most significant byte contains metadata
+	if isCompressed(buf[0]) {
+		if buf[0]&mMask != mCompressedSmallest {
+			return errors.New("invalid compressed GT element: invalid metadata")
+		}
+		buf[0] &= ^mMask
+		return z.SetCompressedBytes(buf[:SizeOfGTCompressed])
+	}
+	read, err = io.ReadFull(dec.r, buf[SizeOfGTCompressed:])
+	dec.n += int64(read)
+	if err != nil {
+		return err
+	}
+	if err = z.SetBytes(buf[:]); err != nil {
+		return err
+	}
+	if dec.subGroupCheck && !z.IsInSubGroup() {
+		return errors.New("invalid GT element: not in the subgroup")
+	}
+	return nil
+}
+
 func isCompressed(msb byte) bool {
 	mData := msb & mMask
 	return !((mData == mUncompressed) || (mData == mUncompressedInfinity))
@@ -363,7 +429,7 @@ func NewEncoder(w io.Writer, options ...func(*Encoder)) *Encoder {
 }
 
 // Encode writes the binary encoding of v to the stream
-// type must be uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, []G1Affine or []G2Affine
+// type must be uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *GT, []G1Affine, []G2Affine or []GT
 func (enc *Encoder) Encode(v interface{}) (err error) {
 	if enc.raw {
 		return enc.encodeRaw(v)
@@ -377,7 +443,7 @@ func (enc *Encoder) BytesWritten() int64 {
 }
 
 // RawEncoding returns an option to use in NewEncoder(...) which sets raw encoding mode to true
-// points will not be compressed using this option
+// points and GT elements will not be compressed using this option
 func RawEncoding() func(*Encoder) {
 	return func(enc *Encoder) {
 		enc.raw = true
@@ -422,6 +488,10 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 		written, err = enc.w.Write(buf[:])
 		enc.n += int64(written)
 		return
+	case *GT:
+		written, err = enc.writeGT(t)
+		enc.n += int64(written)
+		return
 	case []fr.Element:
 		// write slice length
 		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
@@ -495,6 +565,22 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 			}
 		}
 		return nil
+	case []GT:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+
+		for i := 0; i < len(t); i++ {
+			written, err = enc.writeGT(&t[i])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
 	default:
 		n := binary.Size(t)
 		if n == -1 {
@@ -536,6 +622,10 @@ func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 		written, err = enc.w.Write(buf[:])
 		enc.n += int64(written)
 		return
+	case *GT:
+		written, err = enc.writeGTRaw(t)
+		enc.n += int64(written)
+		return
 	case []fr.Element:
 		// write slice length
 		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
@@ -609,6 +699,22 @@ func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 			}
 		}
 		return nil
+	case []GT:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+
+		for i := 0; i < len(t); i++ {
+			written, err = enc.writeGTRaw(&t[i])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
 	default:
 		n := binary.Size(t)
 		if n == -1 {
@@ -620,6 +726,23 @@ func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 	}
 }
 
+// writeGT writes the compressed encoding of z (see GT.CompressedBytes); as
+// for points, the most significant bits flag the compressed form.
+func (enc *Encoder) writeGT(z *GT) (int, error) {
+	buf, err := z.CompressedBytes()
+	if err != nil {
+		return 0, err
+	}
+	buf[0] |= mCompressedSmallest
+	return enc.w.Write(buf[:])
+}
+
+// writeGTRaw writes the raw encoding of z (see GT.Bytes)
+func (enc *Encoder) writeGTRaw(z *GT) (int, error) {
+	buf := z.Bytes()
+	return enc.w.Write(buf[:])
+}
+
 // SizeOfG1AffineCompressed represents the size in bytes that a G1Affine need in binary form, compressed
 const SizeOfG1AffineCompressed = 40
 
@@ -18,7 +18,7 @@ package bls24315
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls24-315/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fptower"
 )
 
 type batchOpG1Affine struct {
@@ -0,0 +1,499 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24315
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// defaultMultiExpTableNbTeeth is the default number of multiples stored per base
+// in a precomputed multi-exponentiation table
+const defaultMultiExpTableNbTeeth = 4
+
+var errNoBases = errors.New("no bases to precompute")
+
+// multiExpTableConfig returns the window size and the comb spacing of a table
+// of nbBases bases, filling the options that are not set with the values that
+// minimize the cost of a multi-exponentiation
+func multiExpTableConfig(nbBases int, opts ...FixedBaseOption) (c, d uint64, err error) {
+	var cfg fixedBaseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c, d = cfg.windowSize, cfg.combSpacing
+
+	spacing := func(c uint64) uint64 {
+		if d != 0 {
+			return d
+		}
+		return (computeNbChunks(c) + defaultMultiExpTableNbTeeth - 1) / defaultMultiExpTableNbTeeth
+	}
+	if c == 0 {
+		// approximate cost (in group operations)
+		// cost = nbBases⋅bits/c + d⋅2^{c}
+		// as all the digits are accumulated in d sets of buckets.
+		min := math.MaxFloat64
+		for _, cc := range []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16} {
+			if checkFixedBaseConfig(cc, spacing(cc)) != nil {
+				continue
+			}
+			cost := float64(uint64(nbBases)*computeNbChunks(cc) + spacing(cc)*(1<<cc))
+			if cost < min {
+				min = cost
+				c = cc
+			}
+		}
+	}
+	d = spacing(c)
+
+	return c, d, checkFixedBaseConfig(c, d)
+}
+
+// G1MultiExpTable holds precomputed multiples of a fixed set of bases in G1Affine,
+// to speed up repeated multi-exponentiations over these bases, such as the
+// commitments to polynomials with a KZG SRS.
+//
+// Scalars are split in signed c-bit digits (see partitionScalars). The table
+// stores the multiples 2^(c⋅d⋅j) ⋅ Pᵢ of each base Pᵢ, where d is the comb
+// spacing, such that the digits j⋅d+l, for all j, can be accumulated in the
+// same buckets. A multi-exponentiation then reduces d sets of buckets instead of
+// ⌈fr.Bits/c⌉, and needs (d-1)⋅c doublings.
+type G1MultiExpTable struct {
+	c, d    uint64
+	nbBases int
+	points  []G1Affine // points[j*nbBases+i] = 2^(c⋅d⋅j) ⋅ bases[i]
+}
+
+// NewG1MultiExpTable precomputes the table of the given bases.
+//
+// The memory budget is set by the comb spacing d (see WithCombSpacing): the table
+// stores ⌈⌈fr.Bits/c⌉/d⌉ multiples of each base, 4 by default. The window
+// size c (see WithWindowSize) defaults to the one minimizing the cost of a
+// multi-exponentiation over all the bases.
+func NewG1MultiExpTable(bases []G1Affine, opts ...FixedBaseOption) (*G1MultiExpTable, error) {
+	if len(bases) == 0 {
+		return nil, errNoBases
+	}
+	c, d, err := multiExpTableConfig(len(bases), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(bases)
+	nbTeeth := int((computeNbChunks(c) + d - 1) / d)
+	t := &G1MultiExpTable{c: c, d: d, nbBases: n}
+	t.points = make([]G1Affine, nbTeeth*n)
+	copy(t.points, bases)
+
+	// each tooth is the previous one times 2^(c⋅d)
+	tooth := make([]G1Jac, n)
+	for j := 1; j < nbTeeth; j++ {
+		prev := t.points[(j-1)*n : j*n]
+		parallel.Execute(n, func(start, end int) {
+			for i := start; i < end; i++ {
+				tooth[i].FromAffine(&prev[i])
+				for k := uint64(0); k < c*d; k++ {
+					tooth[i].DoubleAssign()
+				}
+			}
+		})
+		copy(t.points[j*n:], BatchJacobianToAffineG1(tooth))
+	}
+
+	return t, nil
+}
+
+// Len returns the number of bases of the table
+func (t *G1MultiExpTable) Len() int {
+	return t.nbBases
+}
+
+// MultiExpPrecomputed computes ∑ scalars[i] ⋅ bases[i] with the bases of a precomputed
+// table. There can be fewer scalars than bases, in which case only the first
+// len(scalars) bases are used.
+//
+// This call return an error if len(scalars) > table.Len() or if provided config is invalid.
+func (p *G1Affine) MultiExpPrecomputed(table *G1MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpPrecomputed(table, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes ∑ scalars[i] ⋅ bases[i] with the bases of a precomputed
+// table. There can be fewer scalars than bases, in which case only the first
+// len(scalars) bases are used.
+//
+// This call return an error if len(scalars) > table.Len() or if provided config is invalid.
+func (p *G1Jac) MultiExpPrecomputed(table *G1MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Jac, error) {
+	n := table.nbBases
+	m := len(scalars)
+	if m > n {
+		return nil, errors.New("len(scalars) > number of precomputed bases")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	c, d := table.c, int(table.d)
+	nbChunks := int(computeNbChunks(c))
+	nbTeeth := len(table.points) / n
+
+	// partition the scalars
+	digits, chunkStats := partitionScalars(scalars, c, uint64(nbChunks), config.NbTasks)
+
+	// the digits l, l+d, l+2d... are multiplied by the teeth of the table and
+	// accumulated in the same buckets; each of the d sets of buckets is split
+	// in parts processed in parallel to use the available tasks.
+	nbParts := (config.NbTasks + d - 1) / d
+	if nbParts > nbTeeth {
+		nbParts = nbTeeth
+	}
+	// a part costs a bucket reduction, it is not worth splitting small inputs
+	if maxParts := (nbTeeth * n) >> c; nbParts > maxParts {
+		nbParts = maxParts
+	}
+	if nbParts < 1 {
+		nbParts = 1
+	}
+	chChunks := make([]chan g1JacExtended, d)
+	for l := 0; l < d; l++ {
+		chChunks[l] = make(chan g1JacExtended, 1)
+
+		combDigits := make([]uint16, nbTeeth*n)
+		var stat chunkStat
+		cl := c
+		for j := 0; j < nbTeeth; j++ {
+			chunk := j*d + l
+			if chunk >= nbChunks {
+				break
+			}
+			copy(combDigits[j*n:], digits[chunk*m:(chunk+1)*m])
+			if chunkStats[chunk].nbBucketFilled > stat.nbBucketFilled {
+				stat.nbBucketFilled = chunkStats[chunk].nbBucketFilled
+			}
+			// the last digit may need more buckets
+			if chunk == nbChunks-1 && lastC(c) > c {
+				cl = lastC(c)
+			}
+		}
+		processChunk := getChunkProcessorG1(cl, stat)
+
+		if nbParts == 1 {
+			go processChunk(uint64(l), chChunks[l], c, table.points, combDigits)
+			continue
+		}
+		chSplit := make(chan g1JacExtended, nbParts)
+		partSize := (len(combDigits) + nbParts - 1) / nbParts
+		for start := 0; start < len(combDigits); start += partSize {
+			end := start + partSize
+			if end > len(combDigits) {
+				end = len(combDigits)
+			}
+			go processChunk(uint64(l), chSplit, c, table.points[start:end], combDigits[start:end])
+		}
+		go func(l, nbSplits int) {
+			s := <-chSplit
+			for k := 1; k < nbSplits; k++ {
+				sk := <-chSplit
+				s.add(&sk)
+			}
+			close(chSplit)
+			chChunks[l] <- s
+		}(l, (len(combDigits)+partSize-1)/partSize)
+	}
+
+	return msmReduceChunkG1Affine(p, int(c), chChunks), nil
+}
+
+// WriteTo writes the raw binary encoding of the table
+func (t *G1MultiExpTable) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w, RawEncoding())
+
+	if err := enc.Encode(t.c); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(t.d); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(t.points); err != nil {
+		return enc.BytesWritten(), err
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a table written by WriteTo.
+// The points are checked to be on the curve and in the subgroup, but not to
+// be the multiples of the same bases.
+func (t *G1MultiExpTable) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+
+	if err := dec.Decode(&t.c); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := dec.Decode(&t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := checkFixedBaseConfig(t.c, t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+
+	// the length prefix is checked before allocating the points
+	nbPoints, err := dec.readUint32()
+	if err != nil {
+		return dec.BytesRead(), err
+	}
+	nbTeeth := int((computeNbChunks(t.c) + t.d - 1) / t.d)
+	if nbPoints == 0 || int(nbPoints)%nbTeeth != 0 {
+		return dec.BytesRead(), errInvalidTable
+	}
+	t.points = make([]G1Affine, nbPoints)
+	if err := dec.readG1AffineSlice(t.points); err != nil {
+		return dec.BytesRead(), err
+	}
+	t.nbBases = len(t.points) / nbTeeth
+
+	return dec.BytesRead(), nil
+}
+
+// G2MultiExpTable holds precomputed multiples of a fixed set of bases in G2Affine,
+// to speed up repeated multi-exponentiations over these bases, such as the
+// commitments to polynomials with a KZG SRS.
+//
+// Scalars are split in signed c-bit digits (see partitionScalars). The table
+// stores the multiples 2^(c⋅d⋅j) ⋅ Pᵢ of each base Pᵢ, where d is the comb
+// spacing, such that the digits j⋅d+l, for all j, can be accumulated in the
+// same buckets. A multi-exponentiation then reduces d sets of buckets instead of
+// ⌈fr.Bits/c⌉, and needs (d-1)⋅c doublings.
+type G2MultiExpTable struct {
+	c, d    uint64
+	nbBases int
+	points  []G2Affine // points[j*nbBases+i] = 2^(c⋅d⋅j) ⋅ bases[i]
+}
+
+// NewG2MultiExpTable precomputes the table of the given bases.
+//
+// The memory budget is set by the comb spacing d (see WithCombSpacing): the table
+// stores ⌈⌈fr.Bits/c⌉/d⌉ multiples of each base, 4 by default. The window
+// size c (see WithWindowSize) defaults to the one minimizing the cost of a
+// multi-exponentiation over all the bases.
+func NewG2MultiExpTable(bases []G2Affine, opts ...FixedBaseOption) (*G2MultiExpTable, error) {
+	if len(bases) == 0 {
+		return nil, errNoBases
+	}
+	c, d, err := multiExpTableConfig(len(bases), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(bases)
+	nbTeeth := int((computeNbChunks(c) + d - 1) / d)
+	t := &G2MultiExpTable{c: c, d: d, nbBases: n}
+	t.points = make([]G2Affine, nbTeeth*n)
+	copy(t.points, bases)
+
+	// each tooth is the previous one times 2^(c⋅d)
+	tooth := make([]G2Jac, n)
+	for j := 1; j < nbTeeth; j++ {
+		prev := t.points[(j-1)*n : j*n]
+		parallel.Execute(n, func(start, end int) {
+			for i := start; i < end; i++ {
+				tooth[i].FromAffine(&prev[i])
+				for k := uint64(0); k < c*d; k++ {
+					tooth[i].DoubleAssign()
+				}
+			}
+		})
+		copy(t.points[j*n:], BatchJacobianToAffineG2(tooth))
+	}
+
+	return t, nil
+}
+
+// Len returns the number of bases of the table
+func (t *G2MultiExpTable) Len() int {
+	return t.nbBases
+}
+
+// MultiExpPrecomputed computes ∑ scalars[i] ⋅ bases[i] with the bases of a precomputed
+// table. There can be fewer scalars than bases, in which case only the first
+// len(scalars) bases are used.
+//
+// This call return an error if len(scalars) > table.Len() or if provided config is invalid.
+func (p *G2Affine) MultiExpPrecomputed(table *G2MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Affine, error) {
+	var _p G2Jac
+	if _, err := _p.MultiExpPrecomputed(table, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes ∑ scalars[i] ⋅ bases[i] with the bases of a precomputed
+// table. There can be fewer scalars than bases, in which case only the first
+// len(scalars) bases are used.
+//
+// This call return an error if len(scalars) > table.Len() or if provided config is invalid.
+func (p *G2Jac) MultiExpPrecomputed(table *G2MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Jac, error) {
+	n := table.nbBases
+	m := len(scalars)
+	if m > n {
+		return nil, errors.New("len(scalars) > number of precomputed bases")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	c, d := table.c, int(table.d)
+	nbChunks := int(computeNbChunks(c))
+	nbTeeth := len(table.points) / n
+
+	// partition the scalars
+	digits, chunkStats := partitionScalars(scalars, c, uint64(nbChunks), config.NbTasks)
+
+	// the digits l, l+d, l+2d... are multiplied by the teeth of the table and
+	// accumulated in the same buckets; each of the d sets of buckets is split
+	// in parts processed in parallel to use the available tasks.
+	nbParts := (config.NbTasks + d - 1) / d
+	if nbParts > nbTeeth {
+		nbParts = nbTeeth
+	}
+	// a part costs a bucket reduction, it is not worth splitting small inputs
+	if maxParts := (nbTeeth * n) >> c; nbParts > maxParts {
+		nbParts = maxParts
+	}
+	if nbParts < 1 {
+		nbParts = 1
+	}
+	chChunks := make([]chan g2JacExtended, d)
+	for l := 0; l < d; l++ {
+		chChunks[l] = make(chan g2JacExtended, 1)
+
+		combDigits := make([]uint16, nbTeeth*n)
+		var stat chunkStat
+		cl := c
+		for j := 0; j < nbTeeth; j++ {
+			chunk := j*d + l
+			if chunk >= nbChunks {
+				break
+			}
+			copy(combDigits[j*n:], digits[chunk*m:(chunk+1)*m])
+			if chunkStats[chunk].nbBucketFilled > stat.nbBucketFilled {
+				stat.nbBucketFilled = chunkStats[chunk].nbBucketFilled
+			}
+			// the last digit may need more buckets
+			if chunk == nbChunks-1 && lastC(c) > c {
+				cl = lastC(c)
+			}
+		}
+		processChunk := getChunkProcessorG2(cl, stat)
+
+		if nbParts == 1 {
+			go processChunk(uint64(l), chChunks[l], c, table.points, combDigits)
+			continue
+		}
+		chSplit := make(chan g2JacExtended, nbParts)
+		partSize := (len(combDigits) + nbParts - 1) / nbParts
+		for start := 0; start < len(combDigits); start += partSize {
+			end := start + partSize
+			if end > len(combDigits) {
+				end = len(combDigits)
+			}
+			go processChunk(uint64(l), chSplit, c, table.points[start:end], combDigits[start:end])
+		}
+		go func(l, nbSplits int) {
+			s := <-chSplit
+			for k := 1; k < nbSplits; k++ {
+				sk := <-chSplit
+				s.add(&sk)
+			}
+			close(chSplit)
+			chChunks[l] <- s
+		}(l, (len(combDigits)+partSize-1)/partSize)
+	}
+
+	return msmReduceChunkG2Affine(p, int(c), chChunks), nil
+}
+
+// WriteTo writes the raw binary encoding of the table
+func (t *G2MultiExpTable) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w, RawEncoding())
+
+	if err := enc.Encode(t.c); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(t.d); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(t.points); err != nil {
+		return enc.BytesWritten(), err
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a table written by WriteTo.
+// The points are checked to be on the curve and in the subgroup, but not to
+// be the multiples of the same bases.
+func (t *G2MultiExpTable) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+
+	if err := dec.Decode(&t.c); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := dec.Decode(&t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+	if err := checkFixedBaseConfig(t.c, t.d); err != nil {
+		return dec.BytesRead(), err
+	}
+
+	// the length prefix is checked before allocating the points
+	nbPoints, err := dec.readUint32()
+	if err != nil {
+		return dec.BytesRead(), err
+	}
+	nbTeeth := int((computeNbChunks(t.c) + t.d - 1) / t.d)
+	if nbPoints == 0 || int(nbPoints)%nbTeeth != 0 {
+		return dec.BytesRead(), errInvalidTable
+	}
+	t.points = make([]G2Affine, nbPoints)
+	if err := dec.readG2AffineSlice(t.points); err != nil {
+		return dec.BytesRead(), err
+	}
+	t.nbBases = len(t.points) / nbTeeth
+
+	return dec.BytesRead(), nil
+}
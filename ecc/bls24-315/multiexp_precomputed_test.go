@@ -0,0 +1,344 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24315
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+func TestG1MultiExpTable(t *testing.T) {
+	t.Parallel()
+	const nbBases = 73
+
+	var scalars [nbBases]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	bases := BatchScalarMultiplicationG1(&g1GenAff, scalars[:])
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	// 0 and -1 exercise the corner cases of the digits decomposition
+	scalars[1].SetOne().Neg(&scalars[1])
+	scalars[2].SetZero()
+
+	var expected, expectedPrefix G1Affine
+	if _, err := expected.MultiExp(bases, scalars[:], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expectedPrefix.MultiExp(bases[:nbBases/2], scalars[:nbBases/2], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// window size, comb spacing; 0 uses the default value
+	configs := [][2]int{{0, 0}, {0, 1}, {4, 0}, {2, 8}, {3, 4}, {5, 2}, {9, 3}, {10, 1}, {10, 26}}
+	for _, cfg := range configs {
+		var opts []FixedBaseOption
+		if cfg[0] != 0 {
+			opts = append(opts, WithWindowSize(cfg[0]))
+		}
+		if cfg[1] != 0 {
+			opts = append(opts, WithCombSpacing(cfg[1]))
+		}
+		table, err := NewG1MultiExpTable(bases, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.Len() != nbBases {
+			t.Fatal("wrong number of bases")
+		}
+
+		for _, nbTasks := range []int{1, 3, 64, 0} {
+			var res G1Affine
+			if _, err := res.MultiExpPrecomputed(table, scalars[:], ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(&expected) {
+				t.Fatalf("window size %d, comb spacing %d, %d tasks: MultiExpPrecomputed doesn't match MultiExp", cfg[0], cfg[1], nbTasks)
+			}
+		}
+		var res G1Affine
+		if _, err := res.MultiExpPrecomputed(table, scalars[:nbBases/2], ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expectedPrefix) {
+			t.Fatalf("window size %d, comb spacing %d: MultiExpPrecomputed over a prefix of the bases doesn't match MultiExp", cfg[0], cfg[1])
+		}
+	}
+
+	// serialization round trip
+	table, err := NewG1MultiExpTable(bases, WithWindowSize(9), WithCombSpacing(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res G1Affine
+
+	var buf bytes.Buffer
+	n, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatal("bytes written don't match the buffer length")
+	}
+	var decoded G1MultiExpTable
+	if n, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatal("bytes read don't match bytes written")
+	}
+	if decoded.Len() != nbBases {
+		t.Fatal("decoded table has a different number of bases")
+	}
+	if _, err := res.MultiExpPrecomputed(&decoded, scalars[:], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !res.Equal(&expected) {
+		t.Fatal("decoded table doesn't output the same result")
+	}
+
+	// truncated encoding
+	if _, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Fatal("truncated table should be rejected")
+	}
+
+	// invalid number of points, rejected before decoding the points
+	nbTeeth := uint32((computeNbChunks(table.c) + table.d - 1) / table.d)
+	for _, nbPoints := range []uint32{0, nbTeeth*nbBases + 1, math.MaxUint32} {
+		if nbPoints != 0 && nbPoints%nbTeeth == 0 {
+			continue
+		}
+		header := binary.BigEndian.AppendUint32(append([]byte{}, buf.Bytes()[:16]...), nbPoints)
+		if _, err = decoded.ReadFrom(bytes.NewReader(header)); err != errInvalidTable {
+			t.Fatalf("table of %d points should be rejected", nbPoints)
+		}
+	}
+
+	// invalid inputs
+	table, err = NewG1MultiExpTable(bases[:nbBases-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := res.MultiExpPrecomputed(table, scalars[:], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("more scalars than bases should be rejected")
+	}
+	if _, err := NewG1MultiExpTable(nil); err == nil {
+		t.Fatal("empty bases should be rejected")
+	}
+	for _, cfg := range [][2]int{{1, 1}, {17, 1}, {8, 1000}} {
+		if _, err := NewG1MultiExpTable(bases, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1])); err == nil {
+			t.Fatalf("window size %d and comb spacing %d should be rejected", cfg[0], cfg[1])
+		}
+	}
+}
+
+func BenchmarkG1MultiExpTable(b *testing.B) {
+	const nbSamples = 1 << 16
+	var scalars [nbSamples]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	bases := BatchScalarMultiplicationG1(&g1GenAff, scalars[:])
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+
+	b.Run("MultiExp", func(b *testing.B) {
+		var res G1Affine
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			res.MultiExp(bases, scalars[:], ecc.MultiExpConfig{})
+		}
+	})
+
+	for _, d := range []int{1, 4, 8} {
+		table, _ := NewG1MultiExpTable(bases, WithCombSpacing(d))
+		b.Run(fmt.Sprintf("c=%d,d=%d", table.c, table.d), func(b *testing.B) {
+			var res G1Affine
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				res.MultiExpPrecomputed(table, scalars[:], ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
+
+func TestG2MultiExpTable(t *testing.T) {
+	t.Parallel()
+	const nbBases = 73
+
+	var scalars [nbBases]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	bases := BatchScalarMultiplicationG2(&g2GenAff, scalars[:])
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	// 0 and -1 exercise the corner cases of the digits decomposition
+	scalars[1].SetOne().Neg(&scalars[1])
+	scalars[2].SetZero()
+
+	var expected, expectedPrefix G2Affine
+	if _, err := expected.MultiExp(bases, scalars[:], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expectedPrefix.MultiExp(bases[:nbBases/2], scalars[:nbBases/2], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// window size, comb spacing; 0 uses the default value
+	configs := [][2]int{{0, 0}, {0, 1}, {4, 0}, {2, 8}, {3, 4}, {5, 2}, {9, 3}, {10, 1}, {10, 26}}
+	for _, cfg := range configs {
+		var opts []FixedBaseOption
+		if cfg[0] != 0 {
+			opts = append(opts, WithWindowSize(cfg[0]))
+		}
+		if cfg[1] != 0 {
+			opts = append(opts, WithCombSpacing(cfg[1]))
+		}
+		table, err := NewG2MultiExpTable(bases, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.Len() != nbBases {
+			t.Fatal("wrong number of bases")
+		}
+
+		for _, nbTasks := range []int{1, 3, 64, 0} {
+			var res G2Affine
+			if _, err := res.MultiExpPrecomputed(table, scalars[:], ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(&expected) {
+				t.Fatalf("window size %d, comb spacing %d, %d tasks: MultiExpPrecomputed doesn't match MultiExp", cfg[0], cfg[1], nbTasks)
+			}
+		}
+		var res G2Affine
+		if _, err := res.MultiExpPrecomputed(table, scalars[:nbBases/2], ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(&expectedPrefix) {
+			t.Fatalf("window size %d, comb spacing %d: MultiExpPrecomputed over a prefix of the bases doesn't match MultiExp", cfg[0], cfg[1])
+		}
+	}
+
+	// serialization round trip
+	table, err := NewG2MultiExpTable(bases, WithWindowSize(9), WithCombSpacing(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res G2Affine
+
+	var buf bytes.Buffer
+	n, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatal("bytes written don't match the buffer length")
+	}
+	var decoded G2MultiExpTable
+	if n, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatal("bytes read don't match bytes written")
+	}
+	if decoded.Len() != nbBases {
+		t.Fatal("decoded table has a different number of bases")
+	}
+	if _, err := res.MultiExpPrecomputed(&decoded, scalars[:], ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !res.Equal(&expected) {
+		t.Fatal("decoded table doesn't output the same result")
+	}
+
+	// truncated encoding
+	if _, err = decoded.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Fatal("truncated table should be rejected")
+	}
+
+	// invalid number of points, rejected before decoding the points
+	nbTeeth := uint32((computeNbChunks(table.c) + table.d - 1) / table.d)
+	for _, nbPoints := range []uint32{0, nbTeeth*nbBases + 1, math.MaxUint32} {
+		if nbPoints != 0 && nbPoints%nbTeeth == 0 {
+			continue
+		}
+		header := binary.BigEndian.AppendUint32(append([]byte{}, buf.Bytes()[:16]...), nbPoints)
+		if _, err = decoded.ReadFrom(bytes.NewReader(header)); err != errInvalidTable {
+			t.Fatalf("table of %d points should be rejected", nbPoints)
+		}
+	}
+
+	// invalid inputs
+	table, err = NewG2MultiExpTable(bases[:nbBases-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := res.MultiExpPrecomputed(table, scalars[:], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("more scalars than bases should be rejected")
+	}
+	if _, err := NewG2MultiExpTable(nil); err == nil {
+		t.Fatal("empty bases should be rejected")
+	}
+	for _, cfg := range [][2]int{{1, 1}, {17, 1}, {8, 1000}} {
+		if _, err := NewG2MultiExpTable(bases, WithWindowSize(cfg[0]), WithCombSpacing(cfg[1])); err == nil {
+			t.Fatalf("window size %d and comb spacing %d should be rejected", cfg[0], cfg[1])
+		}
+	}
+}
+
+func BenchmarkG2MultiExpTable(b *testing.B) {
+	const nbSamples = 1 << 16
+	var scalars [nbSamples]fr.Element
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	bases := BatchScalarMultiplicationG2(&g2GenAff, scalars[:])
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+
+	b.Run("MultiExp", func(b *testing.B) {
+		var res G2Affine
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			res.MultiExp(bases, scalars[:], ecc.MultiExpConfig{})
+		}
+	})
+
+	for _, d := range []int{1, 4, 8} {
+		table, _ := NewG2MultiExpTable(bases, WithCombSpacing(d))
+		b.Run(fmt.Sprintf("c=%d,d=%d", table.c, table.d), func(b *testing.B) {
+			var res G2Affine
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				res.MultiExpPrecomputed(table, scalars[:], ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
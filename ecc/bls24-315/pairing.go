@@ -16,17 +16,25 @@ package bls24315
 
 import (
 	"errors"
+	"io"
+	"math/big"
+	"sync"
 
-	"github.com/consensys/gnark-crypto/ecc/bls24-315/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 )
 
 // GT target group of the pairing
 type GT = fptower.E24
 
-type lineEvaluation struct {
-	r0 fptower.E4
-	r1 fptower.E4
-	r2 fptower.E4
+// LineEvaluation holds the coefficients of a line of the Miller loop.
+// Its evaluation at a point (x, y) of G1 is the sparse element of GT with
+// R0⋅y, R1⋅x and R2 at the positions 0, 3 and 4 (see MulBy034).
+type LineEvaluation struct {
+	R0 fptower.E4
+	R1 fptower.E4
+	R2 fptower.E4
 }
 
 // Pair calculates the reduced pairing for a set of points
@@ -145,15 +153,15 @@ func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
 	var result GT
 	result.SetOne()
 
-	var l lineEvaluation
+	var l LineEvaluation
 
 	// len(loopCounter) - 2
 	for k := 0; k < n; k++ {
 		qProj[k].DoubleStep(&l)
 		// line evaluation
-		l.r0.MulByElement(&l.r0, &p[k].Y)
-		l.r1.MulByElement(&l.r1, &p[k].X)
-		result.MulBy034(&l.r0, &l.r1, &l.r2)
+		l.R0.MulByElement(&l.R0, &p[k].Y)
+		l.R1.MulByElement(&l.R1, &p[k].X)
+		result.MulBy034(&l.R0, &l.R1, &l.R2)
 	}
 
 	for i := len(loopCounter) - 3; i >= 0; i-- {
@@ -163,23 +171,23 @@ func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
 		for k := 0; k < n; k++ {
 			qProj[k].DoubleStep(&l)
 			// line evaluation
-			l.r0.MulByElement(&l.r0, &p[k].Y)
-			l.r1.MulByElement(&l.r1, &p[k].X)
-			result.MulBy034(&l.r0, &l.r1, &l.r2)
+			l.R0.MulByElement(&l.R0, &p[k].Y)
+			l.R1.MulByElement(&l.R1, &p[k].X)
+			result.MulBy034(&l.R0, &l.R1, &l.R2)
 
 			if loopCounter[i] == 1 {
 				qProj[k].AddMixedStep(&l, &q[k])
 				// line evaluation
-				l.r0.MulByElement(&l.r0, &p[k].Y)
-				l.r1.MulByElement(&l.r1, &p[k].X)
-				result.MulBy034(&l.r0, &l.r1, &l.r2)
+				l.R0.MulByElement(&l.R0, &p[k].Y)
+				l.R1.MulByElement(&l.R1, &p[k].X)
+				result.MulBy034(&l.R0, &l.R1, &l.R2)
 
 			} else if loopCounter[i] == -1 {
 				qProj[k].AddMixedStep(&l, &qNeg[k])
 				// line evaluation
-				l.r0.MulByElement(&l.r0, &p[k].Y)
-				l.r1.MulByElement(&l.r1, &p[k].X)
-				result.MulBy034(&l.r0, &l.r1, &l.r2)
+				l.R0.MulByElement(&l.R0, &p[k].Y)
+				l.R1.MulByElement(&l.R1, &p[k].X)
+				result.MulBy034(&l.R0, &l.R1, &l.R2)
 			}
 		}
 	}
@@ -189,9 +197,296 @@ func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
 	return result, nil
 }
 
+// PrecomputedLines holds the lines of the Miller loop of a fixed G2 point Q,
+// before their evaluation at a G1 point: [0][i] is the line of the doubling
+// step of iteration i and [1][i] the line of its addition step, if any.
+// The zero value corresponds to Q = ∞.
+type PrecomputedLines [2][len(loopCounter)]LineEvaluation
+
+// SizeOfPrecomputedLines is the size of the binary encoding of PrecomputedLines
+const SizeOfPrecomputedLines = 2 * len(loopCounter) * 3 * 4 * fp.Bytes
+
+// PrecomputeLines computes the lines of the Miller loop of Q, once for all,
+// when Q is a fixed argument of the pairing (see MillerLoopFixedQ).
+func PrecomputeLines(Q G2Affine) (lines PrecomputedLines) {
+	if Q.IsInfinity() {
+		return
+	}
+
+	var qProj g2Proj
+	qProj.FromAffine(&Q)
+	var qNeg G2Affine
+	qNeg.Neg(&Q)
+
+	// i == len(loopCounter) - 2
+	qProj.DoubleStep(&lines[0][len(loopCounter)-2])
+
+	for i := len(loopCounter) - 3; i >= 0; i-- {
+		qProj.DoubleStep(&lines[0][i])
+		if loopCounter[i] == 1 {
+			qProj.AddMixedStep(&lines[1][i], &Q)
+		} else if loopCounter[i] == -1 {
+			qProj.AddMixedStep(&lines[1][i], &qNeg)
+		}
+	}
+
+	return
+}
+
+// MillerLoopFixedQ computes the multi-Miller loop
+// ∏ᵢ MillerLoop(Pᵢ, Qᵢ)
+// where the lines of the Qᵢ are precomputed with PrecomputeLines
+func MillerLoopFixedQ(P []G1Affine, lines []PrecomputedLines) (GT, error) {
+	// check input size match
+	n := len(P)
+	if n == 0 || n != len(lines) {
+		return GT{}, errors.New("invalid inputs sizes")
+	}
+
+	// filter infinity points
+	p := make([]G1Affine, 0, n)
+	q := make([]*PrecomputedLines, 0, n)
+
+	for k := 0; k < n; k++ {
+		if P[k].IsInfinity() || lines[k].isInfinity() {
+			continue
+		}
+		p = append(p, P[k])
+		q = append(q, &lines[k])
+	}
+
+	n = len(p)
+
+	var result GT
+	result.SetOne()
+
+	var l LineEvaluation
+
+	// len(loopCounter) - 2
+	for k := 0; k < n; k++ {
+		l = q[k][0][len(loopCounter)-2]
+		// line evaluation
+		l.R0.MulByElement(&l.R0, &p[k].Y)
+		l.R1.MulByElement(&l.R1, &p[k].X)
+		result.MulBy034(&l.R0, &l.R1, &l.R2)
+	}
+
+	for i := len(loopCounter) - 3; i >= 0; i-- {
+		// (∏ᵢfᵢ)²
+		result.Square(&result)
+
+		for k := 0; k < n; k++ {
+			l = q[k][0][i]
+			// line evaluation
+			l.R0.MulByElement(&l.R0, &p[k].Y)
+			l.R1.MulByElement(&l.R1, &p[k].X)
+			result.MulBy034(&l.R0, &l.R1, &l.R2)
+
+			if loopCounter[i] == 1 {
+				l = q[k][1][i]
+				// line evaluation
+				l.R0.MulByElement(&l.R0, &p[k].Y)
+				l.R1.MulByElement(&l.R1, &p[k].X)
+				result.MulBy034(&l.R0, &l.R1, &l.R2)
+
+			} else if loopCounter[i] == -1 {
+				l = q[k][1][i]
+				// line evaluation
+				l.R0.MulByElement(&l.R0, &p[k].Y)
+				l.R1.MulByElement(&l.R1, &p[k].X)
+				result.MulBy034(&l.R0, &l.R1, &l.R2)
+			}
+		}
+	}
+
+	result.Conjugate(&result)
+
+	return result, nil
+}
+
+// PairingCheckFixedQ calculates the reduced pairing for a set of points and returns True if the result is One
+// ∏ᵢ e(Pᵢ, Qᵢ) =? 1
+// where the lines of the Qᵢ are precomputed with PrecomputeLines
+//
+// This function doesn't check that the inputs are in the correct subgroup. See IsInSubGroup.
+func PairingCheckFixedQ(P []G1Affine, lines []PrecomputedLines) (bool, error) {
+	f, err := MillerLoopFixedQ(P, lines)
+	if err != nil {
+		return false, err
+	}
+	f = FinalExponentiation(&f)
+	var one GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
+
+// residueWitnessParams holds the exponents used to compute and to check the
+// residue witness of a Miller loop output, see FinalExponentiationWitness.
+var residueWitnessParams struct {
+	once sync.Once
+	// λ = p-x₀, a multiple of r
+	lambda big.Int
+	// h₂ is the largest divisor of h = (p²⁴-1)/r whose prime factors divide λ/r
+	h2 big.Int
+	// c = fᵉ for a Miller loop output f
+	e big.Int
+}
+
+func initResidueWitnessParams() {
+	params := &residueWitnessParams
+	q, r := fp.Modulus(), fr.Modulus()
+
+	// λ = p-x₀, where xGen = -x₀
+	params.lambda.Add(q, &xGen)
+
+	// h = (p²⁴-1)/r = h₁⋅h₂ where h₁ is coprime with m = λ/r
+	var h, m, h1, g big.Int
+	h.Exp(q, big.NewInt(24), nil).
+		Sub(&h, big.NewInt(1)).
+		Div(&h, r)
+	m.Div(&params.lambda, r)
+	h1.Set(&h)
+	for g.GCD(nil, nil, &h1, &m); !g.IsUint64() || g.Uint64() != 1; g.GCD(nil, nil, &h1, &m) {
+		h1.Div(&h1, &g)
+	}
+	params.h2.Div(&h, &h1)
+
+	// f = f₁⋅f₂ where f₁ = f^e₁ is of order dividing h₁ and f₂ of order dividing h₂,
+	// with e₁ = 1 mod h₁ and e₁ = 0 mod h₂. λ is invertible mod h₁, so that
+	// c = f₁^(λ⁻¹ mod h₁) satisfies f = c^λ⋅f₂.
+	var e1, lambdaInv big.Int
+	e1.ModInverse(&params.h2, &h1).
+		Mul(&e1, &params.h2)
+	lambdaInv.ModInverse(&params.lambda, &h1)
+	params.e.Mul(&e1, &lambdaInv).
+		Mod(&params.e, &h)
+}
+
+// FinalExponentiationWitness returns the residue witness (c, w) of a Miller loop
+// output f such that FinalExponentiation(f) = 1, for instance the output of
+// MillerLoop for a pairing equation ∏ᵢ e(Pᵢ, Qᵢ) = 1:
+//
+//	f = c^λ ⋅ w
+//
+// where λ = p-x₀ is a multiple of r and w is of order dividing h₂, the largest
+// divisor of (p²⁴-1)/r whose prime factors divide λ/r (h₂ is a 37-bit integer).
+// A verifier, typically a circuit, then checks the equation with an exponentiation
+// by λ, which costs one exponentiation by x₀ and a Frobenius map, instead of the final exponentiation.
+// See "On Proving Pairings" (Novakovic and Eagen, https://eprint.iacr.org/2024/640.pdf).
+//
+// It returns an error if FinalExponentiation(f) ≠ 1.
+func FinalExponentiationWitness(f *GT) (c, w GT, err error) {
+	params := &residueWitnessParams
+	params.once.Do(initResidueWitnessParams)
+
+	c.Exp(*f, &params.e)
+	// w = f ⋅ c^(-λ)
+	w.Exp(c, &params.lambda).
+		Inverse(&w).
+		Mul(&w, f)
+
+	if !CheckFinalExponentiationWitness(f, &c, &w) {
+		return GT{}, GT{}, errors.New("final exponentiation is not one")
+	}
+	return c, w, nil
+}
+
+// CheckFinalExponentiationWitness returns true if f = c^λ ⋅ w and w^h₂ = 1 (see
+// FinalExponentiationWitness), which proves that FinalExponentiation(f) = 1.
+func CheckFinalExponentiationWitness(f, c, w *GT) bool {
+	params := &residueWitnessParams
+	params.once.Do(initResidueWitnessParams)
+
+	var t GT
+	t.Exp(*w, &params.h2)
+	if !t.IsOne() {
+		return false
+	}
+	t.Exp(*c, &params.lambda).
+		Mul(&t, w)
+	return t.Equal(f)
+}
+
+// CheckPairingHints returns true if the hints of a pairing equation prove
+// ∏ᵢ e(Pᵢ, Qᵢ) = 1, that is if lines[i] are the lines of the Miller loop of Qᵢ
+// (see PrecomputeLines) and (c, w) is the residue witness of the Miller loop
+// output computed from these lines (see FinalExponentiationWitness).
+// It is the native counterpart of the verification of the hints in a circuit.
+//
+// This function doesn't check that the inputs are in the correct subgroup. See IsInSubGroup.
+func CheckPairingHints(P []G1Affine, Q []G2Affine, lines []PrecomputedLines, c, w *GT) (bool, error) {
+	if len(Q) != len(lines) {
+		return false, errors.New("invalid inputs sizes")
+	}
+	for i := range Q {
+		if PrecomputeLines(Q[i]) != lines[i] {
+			return false, nil
+		}
+	}
+	f, err := MillerLoopFixedQ(P, lines)
+	if err != nil {
+		return false, err
+	}
+	return CheckFinalExponentiationWitness(&f, c, w), nil
+}
+
+// isInfinity returns true if lines is the zero value, i.e. the lines of Q = ∞
+func (lines *PrecomputedLines) isInfinity() bool {
+	// the first doubling line of Q ≠ ∞ is not zero
+	l := &lines[0][len(loopCounter)-2]
+	return l.R0.IsZero() && l.R1.IsZero() && l.R2.IsZero()
+}
+
+// WriteTo writes the binary encoding of the lines in w: the fp coordinates
+// of the coefficients of the lines, in big-endian.
+func (lines *PrecomputedLines) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 0, SizeOfPrecomputedLines)
+	for i := range lines {
+		for j := range lines[i] {
+			for _, e := range lines[i][j].coordinates() {
+				b := e.Bytes()
+				buf = append(buf, b[:]...)
+			}
+		}
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadFrom reads the binary encoding of lines written by WriteTo.
+// The coordinates must be canonical, but the lines are not checked to be the
+// ones of a point of G2: they must come from a trusted source.
+func (lines *PrecomputedLines) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, SizeOfPrecomputedLines)
+	n, err := io.ReadFull(r, buf)
+	if err != nil {
+		return int64(n), err
+	}
+	for i := range lines {
+		for j := range lines[i] {
+			for _, e := range lines[i][j].coordinates() {
+				if err := e.SetBytesCanonical(buf[:fp.Bytes]); err != nil {
+					return int64(n), err
+				}
+				buf = buf[fp.Bytes:]
+			}
+		}
+	}
+	return int64(n), nil
+}
+
+// coordinates returns the fp coordinates of the coefficients of l
+func (l *LineEvaluation) coordinates() [3 * 4]*fp.Element {
+	return [3 * 4]*fp.Element{
+		&l.R0.B0.A0, &l.R0.B0.A1, &l.R0.B1.A0, &l.R0.B1.A1,
+		&l.R1.B0.A0, &l.R1.B0.A1, &l.R1.B1.A0, &l.R1.B1.A1,
+		&l.R2.B0.A0, &l.R2.B0.A1, &l.R2.B1.A0, &l.R2.B1.A1,
+	}
+}
+
 // DoubleStep doubles a point in Homogenous projective coordinates, and evaluates the line in Miller loop
 // https://eprint.iacr.org/2013/722.pdf (Section 4.3)
-func (p *g2Proj) DoubleStep(evaluations *lineEvaluation) {
+func (p *g2Proj) DoubleStep(evaluations *LineEvaluation) {
 
 	// get some Element from our pool
 	var t1, A, B, C, D, E, EE, F, G, H, I, J, K fptower.E4
@@ -224,15 +519,15 @@ func (p *g2Proj) DoubleStep(evaluations *lineEvaluation) {
 	p.z.Mul(&B, &H)
 
 	// Line evaluation
-	evaluations.r0.Neg(&H)
-	evaluations.r1.Double(&J).
-		Add(&evaluations.r1, &J)
-	evaluations.r2.Set(&I)
+	evaluations.R0.Neg(&H)
+	evaluations.R1.Double(&J).
+		Add(&evaluations.R1, &J)
+	evaluations.R2.Set(&I)
 }
 
 // AddMixedStep point addition in Mixed Homogenous projective and Affine coordinates
 // https://eprint.iacr.org/2013/722.pdf (Section 4.3)
-func (p *g2Proj) AddMixedStep(evaluations *lineEvaluation, a *G2Affine) {
+func (p *g2Proj) AddMixedStep(evaluations *LineEvaluation, a *G2Affine) {
 
 	// get some Element from our pool
 	var Y2Z1, X2Z1, O, L, C, D, E, F, G, H, t0, t1, t2, J fptower.E4
@@ -262,7 +557,7 @@ func (p *g2Proj) AddMixedStep(evaluations *lineEvaluation, a *G2Affine) {
 		Sub(&J, &t2)
 
 	// Line evaluation
-	evaluations.r0.Set(&L)
-	evaluations.r1.Neg(&O)
-	evaluations.r2.Set(&J)
+	evaluations.R0.Set(&L)
+	evaluations.R1.Neg(&O)
+	evaluations.R2.Set(&J)
 }
@@ -30,6 +30,14 @@ func (z *E4) Equal(x *E4) bool {
 	return z.B0.Equal(&x.B0) && z.B1.Equal(&x.B1)
 }
 
+// Select is a constant-time conditional move.
+// If cond=0, z = caseZ. Else z = caseNz
+func (z *E4) Select(cond int, caseZ *E4, caseNz *E4) *E4 {
+	z.B0.Select(cond, &caseZ.B0, &caseNz.B0)
+	z.B1.Select(cond, &caseZ.B1, &caseNz.B1)
+	return z
+}
+
 // Cmp compares (lexicographic order) z and x and returns:
 //
 //	-1 if z <  x
@@ -0,0 +1,356 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInconsistentSRS     = errors.New("srs is not made of the successive powers of τ")
+	ErrInvalidContribution = errors.New("invalid contribution to the powers of τ ceremony")
+	ErrInvalidBeacon       = errors.New("contribution doesn't derive from the random beacon")
+	ErrInvalidNbIterations = errors.New("number of iterations of the random beacon must be in [0, 63]")
+)
+
+// domain separation tags of the powers of τ ceremony
+var (
+	contributionPoKDST    = []byte("GNARK-CRYPTO-KZG-POWERS-OF-TAU-POK")
+	contributionSecretDST = []byte("GNARK-CRYPTO-KZG-POWERS-OF-TAU-SECRET")
+)
+
+// contributionEntropySize is the number of bytes read from the randomness source of a contribution
+const contributionEntropySize = 64
+
+// ContributionProof proves that a contribution to a powers of τ ceremony updated
+// the SRS with the powers of a secret x known to the contributor, that is τ' = x⋅τ.
+//
+// implements io.ReaderFrom and io.WriterTo
+type ContributionProof struct {
+	// Public [x]G₁
+	Public bls24317.G1Affine
+
+	// PoK [x]R, where R is the hash to G₂ of the challenge and of Public
+	PoK bls24317.G2Affine
+
+	// Hash of the challenge and of the proof of knowledge, where the challenge is the
+	// hash of the SRS before the contribution. As the SRS after the contribution is the
+	// challenge of the next one, the hashes of the successive contributions form a chain.
+	Hash [sha256.Size]byte
+}
+
+// Contribute updates srs in place with a secret x derived from randomness (typically
+// crypto/rand.Reader) and returns the proof that the contributor knew x.
+//
+// The powers of τ become the powers of x⋅τ, so that τ stays unknown as long as one of the
+// contributors discards its secret. The scalar multiplications by x and its powers
+// are constant time.
+func Contribute(srs *SRS, randomness io.Reader) (ContributionProof, error) {
+	var entropy [contributionEntropySize]byte
+	if _, err := io.ReadFull(randomness, entropy[:]); err != nil {
+		return ContributionProof{}, err
+	}
+	return contribute(srs, entropy[:])
+}
+
+// ContributeBeacon updates srs in place with a secret derived from a public random beacon,
+// hashed 2^nbIterations times, and returns the proof of the contribution. nbIterations
+// must be in [0, 63].
+//
+// It is meant to be the last contribution of a ceremony, so that the final τ doesn't depend
+// only on the choices of the previous contributors. Anyone can check it with VerifyBeacon.
+func ContributeBeacon(srs *SRS, beacon []byte, nbIterations int) (ContributionProof, error) {
+	entropy, err := beaconEntropy(beacon, nbIterations)
+	if err != nil {
+		return ContributionProof{}, err
+	}
+	return contribute(srs, entropy)
+}
+
+// VerifyContribution checks that next is the SRS obtained from prev through the contribution
+// proved by proof.
+//
+// It checks that
+//   - next has as many powers as prev and starts with the generators of G₁ and G₂,
+//   - the proof of knowledge of the secret x of the contribution holds,
+//   - next.G1[1] = [x⋅τ]G₁ where [τ]G₂ = prev.G2[1], and next.G2[1] = [x⋅τ]G₂,
+//   - next.G1 is made of the successive powers of x⋅τ,
+//
+// the pairing equations being checked at once with BatchPairingCheck. The points of next are
+// assumed to be in the correct subgroups, which the decoder checks by default.
+func VerifyContribution(prev, next *SRS, proof *ContributionProof) error {
+	if len(next.G1) != len(prev.G1) {
+		return ErrInvalidContribution
+	}
+	if len(next.G1) < 2 {
+		return ErrMinSRSSize
+	}
+	_, _, g1, g2 := bls24317.Generators()
+	if !next.G1[0].Equal(&g1) || !next.G2[0].Equal(&g2) {
+		return ErrInconsistentSRS
+	}
+
+	// x ≠ 0
+	if proof.Public.IsInfinity() || !proof.Public.IsInSubGroup() || !proof.PoK.IsInSubGroup() {
+		return ErrInvalidContribution
+	}
+
+	challenge, err := srsHash(prev)
+	if err != nil {
+		return err
+	}
+	if proof.Hash != contributionHash(challenge, &proof.Public, &proof.PoK) {
+		return ErrInvalidContribution
+	}
+	r, err := pokBase(challenge, &proof.Public)
+	if err != nil {
+		return err
+	}
+
+	powersG1, powersG2, err := powersOfTauEquation(next)
+	if err != nil {
+		return err
+	}
+
+	var negG1, negPublic bls24317.G1Affine
+	negG1.Neg(&g1)
+	negPublic.Neg(&proof.Public)
+	P := [][]bls24317.G1Affine{
+		// e([x]G₁, R) == e(G₁, [x]R)
+		{proof.Public, negG1},
+		// e([x⋅τ]G₁, G₂) == e([x]G₁, [τ]G₂)
+		{next.G1[1], negPublic},
+		// e([x⋅τ]G₁, G₂) == e(G₁, [x⋅τ]G₂)
+		{next.G1[1], negG1},
+		powersG1,
+	}
+	Q := [][]bls24317.G2Affine{
+		{r, proof.PoK},
+		{g2, prev.G2[1]},
+		{g2, next.G2[1]},
+		powersG2,
+	}
+	failing, err := bls24317.BatchPairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if len(failing) != 0 {
+		if failing[0] == len(P)-1 {
+			return ErrInconsistentSRS
+		}
+		return ErrInvalidContribution
+	}
+	return nil
+}
+
+// VerifyBeacon checks that next is the SRS obtained from prev through the contribution of
+// the random beacon hashed 2^nbIterations times (see ContributeBeacon).
+func VerifyBeacon(prev, next *SRS, proof *ContributionProof, beacon []byte, nbIterations int) error {
+	challenge, err := srsHash(prev)
+	if err != nil {
+		return err
+	}
+	entropy, err := beaconEntropy(beacon, nbIterations)
+	if err != nil {
+		return err
+	}
+	x, err := contributionSecret(entropy, challenge)
+	if err != nil {
+		return err
+	}
+	_, _, g1, _ := bls24317.Generators()
+	var public bls24317.G1Affine
+	public.ScalarMultiplication(&g1, x.BigInt(new(big.Int)))
+	if !public.Equal(&proof.Public) {
+		return ErrInvalidBeacon
+	}
+	return VerifyContribution(prev, next, proof)
+}
+
+// CheckSRS checks that the SRS starts with the generators of G₁ and G₂ and that
+// srs.G1 is made of the successive powers of the τ of srs.G2[1], that is
+//
+//	e([τⁱ⁺¹]G₁, G₂) == e([τⁱ]G₁, [τ]G₂)
+//
+// for all i. The equations are checked at once with a random linear combination
+// of the G₁ points, so the points must be in the subgroups.
+func CheckSRS(srs *SRS) error {
+	_, _, g1, g2 := bls24317.Generators()
+	if len(srs.G1) < 2 {
+		return ErrMinSRSSize
+	}
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		return ErrInconsistentSRS
+	}
+
+	P, Q, err := powersOfTauEquation(srs)
+	if err != nil {
+		return err
+	}
+	ok, err := bls24317.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInconsistentSRS
+	}
+	return nil
+}
+
+// powersOfTauEquation returns the pairing equation
+//
+//	e(∑ᵢρⁱ[τⁱ⁺¹]G₁, G₂) == e(∑ᵢρⁱ[τⁱ]G₁, [τ]G₂)
+//
+// for a random ρ, which holds if and only if srs.G1 is made of the successive powers of
+// the τ of srs.G2[1], except with negligible probability. It also checks that τ ∉ {0, 1}.
+func powersOfTauEquation(srs *SRS) ([]bls24317.G1Affine, []bls24317.G2Affine, error) {
+	if srs.G1[1].IsInfinity() || srs.G1[1].Equal(&srs.G1[0]) {
+		return nil, nil, ErrInconsistentSRS
+	}
+
+	n := len(srs.G1) - 1
+	rho := make([]fr.Element, n)
+	rho[0].SetOne()
+	if n > 1 {
+		if _, err := rho[1].SetRandom(); err != nil {
+			return nil, nil, err
+		}
+	}
+	for i := 2; i < n; i++ {
+		rho[i].Mul(&rho[i-1], &rho[1])
+	}
+
+	var left, right bls24317.G1Affine
+	if _, err := left.MultiExp(srs.G1[1:], rho, ecc.MultiExpConfig{}); err != nil {
+		return nil, nil, err
+	}
+	if _, err := right.MultiExp(srs.G1[:n], rho, ecc.MultiExpConfig{}); err != nil {
+		return nil, nil, err
+	}
+	right.Neg(&right)
+
+	return []bls24317.G1Affine{left, right}, []bls24317.G2Affine{srs.G2[0], srs.G2[1]}, nil
+}
+
+// contribute updates srs with the secret derived from entropy and the hash of srs
+func contribute(srs *SRS, entropy []byte) (ContributionProof, error) {
+	if len(srs.G1) < 2 {
+		return ContributionProof{}, ErrMinSRSSize
+	}
+	challenge, err := srsHash(srs)
+	if err != nil {
+		return ContributionProof{}, err
+	}
+	x, err := contributionSecret(entropy, challenge)
+	if err != nil {
+		return ContributionProof{}, err
+	}
+	var bX big.Int
+	x.BigInt(&bX)
+
+	var proof ContributionProof
+	proof.Public.ScalarMultiplicationCT(&srs.G1[0], &bX)
+	r, err := pokBase(challenge, &proof.Public)
+	if err != nil {
+		return ContributionProof{}, err
+	}
+	proof.PoK.ScalarMultiplicationCT(&r, &bX)
+	proof.Hash = contributionHash(challenge, &proof.Public, &proof.PoK)
+
+	// [τⁱ]G₁ ← [xⁱ⋅τⁱ]G₁; x is secret, hence the constant time scalar multiplications
+	parallel.Execute(len(srs.G1), func(start, end int) {
+		var xi fr.Element
+		var bXi big.Int
+		xi.Exp(x, big.NewInt(int64(start)))
+		points := make([]bls24317.G1Jac, end-start)
+		for i := start; i < end; i++ {
+			points[i-start].FromAffine(&srs.G1[i])
+			points[i-start].ScalarMultiplicationCT(&points[i-start], xi.BigInt(&bXi))
+			xi.Mul(&xi, &x)
+		}
+		copy(srs.G1[start:end], bls24317.BatchJacobianToAffineG1(points))
+	})
+	srs.G2[1].ScalarMultiplicationCT(&srs.G2[1], &bX)
+
+	return proof, nil
+}
+
+// contributionSecret derives the secret of a contribution from its entropy and challenge
+func contributionSecret(entropy, challenge []byte) (fr.Element, error) {
+	msg := make([]byte, 0, len(entropy)+len(challenge))
+	msg = append(msg, entropy...)
+	msg = append(msg, challenge...)
+	x, err := fr.Hash(msg, contributionSecretDST, 1)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	if x[0].IsZero() {
+		return fr.Element{}, ErrInvalidContribution
+	}
+	return x[0], nil
+}
+
+// beaconEntropy hashes the beacon 2^nbIterations times
+func beaconEntropy(beacon []byte, nbIterations int) ([]byte, error) {
+	if nbIterations < 0 || nbIterations > 63 {
+		return nil, ErrInvalidNbIterations
+	}
+	h := sha256.Sum256(beacon)
+	for i := uint64(1); i < uint64(1)<<nbIterations; i++ {
+		h = sha256.Sum256(h[:])
+	}
+	return h[:], nil
+}
+
+// srsHash returns the hash of the binary encoding of the SRS, challenge of the next contribution
+func srsHash(srs *SRS) ([]byte, error) {
+	h := sha256.New()
+	if _, err := srs.WriteTo(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// pokBase returns the point R of the proof of knowledge [x]R of the secret of a contribution
+func pokBase(challenge []byte, public *bls24317.G1Affine) (bls24317.G2Affine, error) {
+	b := public.Bytes()
+	msg := make([]byte, 0, len(challenge)+len(b))
+	msg = append(msg, challenge...)
+	msg = append(msg, b[:]...)
+	return bls24317.HashToG2(msg, contributionPoKDST)
+}
+
+// contributionHash returns the hash of a contribution
+func contributionHash(challenge []byte, public *bls24317.G1Affine, pok *bls24317.G2Affine) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(challenge)
+	b1 := public.Bytes()
+	h.Write(b1[:])
+	b2 := pok.Bytes()
+	h.Write(b2[:])
+	var res [sha256.Size]byte
+	h.Sum(res[:0])
+	return res
+}
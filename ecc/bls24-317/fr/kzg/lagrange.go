@@ -0,0 +1,226 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/fft"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidDomainSize = errors.New("size of the evaluations, the domain and the lagrange srs don't match")
+)
+
+// SRSLagrange stores the powers of τ of an SRS in Lagrange form on a fft.Domain:
+// G1[i] = [Lᵢ(τ)]G₁, where Lᵢ is the i-th Lagrange polynomial of the domain, in
+// natural order (Lᵢ(ωⁱ) = 1).
+//
+// A commitment to the evaluations of a polynomial on the domain is the commitment to
+// the polynomial with the canonical SRS, so the opening proofs are checked with Verify.
+//
+// implements io.ReaderFrom and io.WriterTo
+type SRSLagrange struct {
+	G1 []bls24317.G1Affine // [L₀(τ)]G₁, [L₁(τ)]G₁, ...
+}
+
+// NewSRSLagrange returns the Lagrange form of srs on domain, which must not be larger than srs.
+func NewSRSLagrange(srs *SRS, domain *fft.Domain) (*SRSLagrange, error) {
+	if domain.Cardinality > uint64(len(srs.G1)) {
+		return nil, ErrInvalidDomainSize
+	}
+	g1, err := ToLagrangeG1(srs.G1[:domain.Cardinality], domain)
+	if err != nil {
+		return nil, err
+	}
+	return &SRSLagrange{G1: g1}, nil
+}
+
+// ToLagrangeG1 returns the points [Lᵢ(τ)]G₁ from the points [τⁱ]G₁ for i < domain.Cardinality,
+// by computing the inverse FFT of the powers of τ in G₁:
+//
+//	[Lᵢ(τ)]G₁ = 1/n ∑ⱼ ω⁻ⁱʲ[τʲ]G₁
+func ToLagrangeG1(powers []bls24317.G1Affine, domain *fft.Domain) ([]bls24317.G1Affine, error) {
+	n := len(powers)
+	if uint64(n) != domain.Cardinality {
+		return nil, ErrInvalidDomainSize
+	}
+
+	points := make([]bls24317.G1Jac, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			points[i].FromAffine(&powers[i])
+		}
+	})
+
+	difFFTG1(points, domain.TwiddlesInv)
+	bitReverseG1(points)
+
+	var bCardinalityInv big.Int
+	domain.CardinalityInv.BigInt(&bCardinalityInv)
+	res := make([]bls24317.G1Affine, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			points[i].ScalarMultiplication(&points[i], &bCardinalityInv)
+		}
+		copy(res[start:end], bls24317.BatchJacobianToAffineG1(points[start:end]))
+	})
+
+	return res, nil
+}
+
+// CommitLagrange commits to a polynomial given by its evaluations on the domain of the
+// Lagrange SRS, in natural order, using a multi exponentiation. The commitment is the
+// same as Commit of the polynomial in canonical form.
+func CommitLagrange(evaluations []fr.Element, srs *SRSLagrange, nbTasks ...int) (Digest, error) {
+	if len(evaluations) != len(srs.G1) {
+		return Digest{}, ErrInvalidDomainSize
+	}
+
+	var res bls24317.G1Affine
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	if _, err := res.MultiExp(srs.G1, evaluations, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
+// OpenLagrange computes an opening proof at point of a polynomial given by its evaluations
+// on domain, in natural order. The claimed value and the quotient (f - f(point))/(X - point)
+// are computed in evaluation form, point being on the domain or not, so that the proof is
+// the same as the one of Open for the polynomial in canonical form.
+func OpenLagrange(evaluations []fr.Element, point fr.Element, domain *fft.Domain, srs *SRSLagrange) (OpeningProof, error) {
+	n := len(evaluations)
+	if uint64(n) != domain.Cardinality || n != len(srs.G1) {
+		return OpeningProof{}, ErrInvalidDomainSize
+	}
+
+	// ωⁱ
+	omegas := make([]fr.Element, n)
+	omegas[0].SetOne()
+	for i := 1; i < n; i++ {
+		omegas[i].Mul(&omegas[i-1], &domain.Generator)
+	}
+
+	// 1/(point - ωⁱ), 0 if point = ωⁱ
+	inDomain := -1
+	denominators := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		denominators[i].Sub(&point, &omegas[i])
+		if denominators[i].IsZero() {
+			inDomain = i
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res OpeningProof
+	if inDomain == -1 {
+		// f(point) = (pointⁿ-1)/n ∑ᵢ ωⁱfᵢ/(point - ωⁱ)
+		var t fr.Element
+		for i := 0; i < n; i++ {
+			t.Mul(&omegas[i], &denominators[i]).Mul(&t, &evaluations[i])
+			res.ClaimedValue.Add(&res.ClaimedValue, &t)
+		}
+		var zn fr.Element
+		zn.Exp(point, big.NewInt(int64(n)))
+		t.SetOne()
+		zn.Sub(&zn, &t).Mul(&zn, &domain.CardinalityInv)
+		res.ClaimedValue.Mul(&res.ClaimedValue, &zn)
+	} else {
+		res.ClaimedValue.Set(&evaluations[inDomain])
+	}
+
+	// qᵢ = (fᵢ - f(point))/(ωⁱ - point)
+	quotient := make([]fr.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			quotient[i].Sub(&res.ClaimedValue, &evaluations[i]).Mul(&quotient[i], &denominators[i])
+		}
+	})
+
+	if inDomain != -1 {
+		// q(ωᵏ) = ∑_{i≠k} (fᵢ - fₖ)ωⁱ / (ωᵏ(ωᵏ - ωⁱ)) = -∑_{i≠k} qᵢωⁱ⁻ᵏ
+		var qk, t fr.Element
+		quotient[inDomain].SetZero()
+		for i := 0; i < n; i++ {
+			t.Mul(&quotient[i], &omegas[(i-inDomain+n)%n])
+			qk.Sub(&qk, &t)
+		}
+		quotient[inDomain] = qk
+	}
+
+	var err error
+	res.H, err = CommitLagrange(quotient, srs)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// difFFTG1 computes the FFT of a in G₁ with the given twiddle factors, with decimation
+// in frequency (the output is in bit-reversed order). Each stage is parallelized as
+// the scalar multiplications make the butterflies expensive.
+func difFFTG1(a []bls24317.G1Jac, twiddles [][]fr.Element) {
+	n := len(a)
+	nbStages := bits.TrailingZeros(uint(n))
+	for stage := 0; stage < nbStages; stage++ {
+		m := n >> (stage + 1)
+		// twiddles[stage][i] as big.Int
+		bTwiddles := make([]big.Int, m)
+		for i := 0; i < m; i++ {
+			twiddles[stage][i].BigInt(&bTwiddles[i])
+		}
+		parallel.Execute(n>>1, func(start, end int) {
+			var t bls24317.G1Jac
+			for j := start; j < end; j++ {
+				i := j % m
+				k := (j/m)*2*m + i
+				t.Set(&a[k])
+				a[k].AddAssign(&a[k+m])
+				a[k+m].Neg(&a[k+m]).AddAssign(&t)
+				if i != 0 {
+					a[k+m].ScalarMultiplication(&a[k+m], &bTwiddles[i])
+				}
+			}
+		})
+	}
+}
+
+// bitReverseG1 applies the bit-reversal permutation to a, whose length is a power of 2.
+func bitReverseG1(a []bls24317.G1Jac) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
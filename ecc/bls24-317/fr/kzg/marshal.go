@@ -61,6 +61,20 @@ func (srs *SRS) ReadFrom(r io.Reader) (int64, error) {
 	return dec.BytesRead(), nil
 }
 
+// WriteTo writes binary encoding of the SRSLagrange
+func (srs *SRSLagrange) WriteTo(w io.Writer) (int64, error) {
+	enc := bls24317.NewEncoder(w)
+	err := enc.Encode(srs.G1)
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes SRSLagrange data from reader.
+func (srs *SRSLagrange) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls24317.NewDecoder(r)
+	err := dec.Decode(&srs.G1)
+	return dec.BytesRead(), err
+}
+
 // WriteTo writes binary encoding of a OpeningProof
 func (proof *OpeningProof) WriteTo(w io.Writer) (int64, error) {
 	enc := bls24317.NewEncoder(w)
@@ -132,3 +146,41 @@ func (proof *BatchOpeningProof) ReadFrom(r io.Reader) (int64, error) {
 
 	return dec.BytesRead(), nil
 }
+
+// WriteTo writes binary encoding of a ContributionProof
+func (proof *ContributionProof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls24317.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.Public,
+		&proof.PoK,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	n, err := w.Write(proof.Hash[:])
+	return enc.BytesWritten() + int64(n), err
+}
+
+// ReadFrom decodes ContributionProof data from reader.
+func (proof *ContributionProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls24317.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.Public,
+		&proof.PoK,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	n, err := io.ReadFull(r, proof.Hash[:])
+	return dec.BytesRead() + int64(n), err
+}
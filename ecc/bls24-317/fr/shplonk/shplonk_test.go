@@ -0,0 +1,210 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package shplonk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/kzg"
+)
+
+// testSRS re-used accross tests of the shplonk scheme
+var testSRS *kzg.SRS
+
+func init() {
+	const srsSize = 64
+	testSRS, _ = kzg.NewSRS(srsSize, new(big.Int).SetInt64(42))
+}
+
+// randomClaims returns polynomials of various sizes with their commitments, opened at ζ,
+// ωζ or both like in PLONK, and at extra points
+func randomClaims(t *testing.T) ([][]fr.Element, []kzg.Digest, [][]fr.Element) {
+	var zeta, omegaZeta, x fr.Element
+	zeta.SetRandom()
+	omegaZeta.SetUint64(7).Mul(&omegaZeta, &zeta)
+	x.SetRandom()
+
+	sizes := []int{len(testSRS.G1), 1, 20, 33, 2, 40}
+	points := [][]fr.Element{
+		{zeta},
+		{zeta, omegaZeta},
+		{omegaZeta},
+		{x, zeta, omegaZeta},
+		{omegaZeta, x},
+		{zeta},
+	}
+
+	polynomials := make([][]fr.Element, len(sizes))
+	digests := make([]kzg.Digest, len(sizes))
+	for i, size := range sizes {
+		polynomials[i] = make([]fr.Element, size)
+		for j := range polynomials[i] {
+			polynomials[i][j].SetRandom()
+		}
+		var err error
+		if digests[i], err = kzg.Commit(polynomials[i], testSRS); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return polynomials, digests, points
+}
+
+func TestOpening(t *testing.T) {
+	polynomials, digests, points := randomClaims(t)
+	data := []byte("transcript of the protocol")
+
+	proof, err := BatchOpen(polynomials, digests, points, sha256.New(), testSRS, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range points {
+		for j := range points[i] {
+			if expected := eval(polynomials[i], points[i][j]); !proof.ClaimedValues[i][j].Equal(&expected) {
+				t.Fatal("wrong claimed value")
+			}
+		}
+	}
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS, data); err != nil {
+		t.Fatal(err)
+	}
+
+	// the transcript data must match
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS); err != ErrVerifyOpeningProof {
+		t.Fatalf("expected ErrVerifyOpeningProof, got %v", err)
+	}
+
+	// wrong claimed value
+	proof.ClaimedValues[3][1].Add(&proof.ClaimedValues[3][1], &proof.ClaimedValues[0][0])
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS, data); err != ErrVerifyOpeningProof {
+		t.Fatalf("expected ErrVerifyOpeningProof, got %v", err)
+	}
+	proof.ClaimedValues[3][1].Sub(&proof.ClaimedValues[3][1], &proof.ClaimedValues[0][0])
+
+	// wrong digest
+	digests[2], digests[4] = digests[4], digests[2]
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS, data); err != ErrVerifyOpeningProof {
+		t.Fatalf("expected ErrVerifyOpeningProof, got %v", err)
+	}
+	digests[2], digests[4] = digests[4], digests[2]
+
+	// wrong points
+	points[1][0], points[1][1] = points[1][1], points[1][0]
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS, data); err != ErrVerifyOpeningProof {
+		t.Fatalf("expected ErrVerifyOpeningProof, got %v", err)
+	}
+	points[1][0], points[1][1] = points[1][1], points[1][0]
+
+	// wrong proof
+	proof.W, proof.WPrime = proof.WPrime, proof.W
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS, data); err != ErrVerifyOpeningProof {
+		t.Fatalf("expected ErrVerifyOpeningProof, got %v", err)
+	}
+	proof.W, proof.WPrime = proof.WPrime, proof.W
+
+	if err := BatchVerify(&proof, digests, points, sha256.New(), testSRS, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpeningInvalidInputs(t *testing.T) {
+	polynomials, digests, points := randomClaims(t)
+
+	if _, err := BatchOpen(polynomials, digests[1:], points, sha256.New(), testSRS); err != ErrInvalidNbDigests {
+		t.Fatalf("expected ErrInvalidNbDigests, got %v", err)
+	}
+	points[3][2] = points[3][1]
+	if _, err := BatchOpen(polynomials, digests, points, sha256.New(), testSRS); err != ErrInvalidPointSet {
+		t.Fatalf("expected ErrInvalidPointSet, got %v", err)
+	}
+	points[3] = nil
+	if _, err := BatchOpen(polynomials, digests, points, sha256.New(), testSRS); err != ErrInvalidPointSet {
+		t.Fatalf("expected ErrInvalidPointSet, got %v", err)
+	}
+	polynomials[0] = append(polynomials[0], fr.One())
+	if _, err := BatchOpen(polynomials, digests, points, sha256.New(), testSRS); err != ErrInvalidPolynomialSize {
+		t.Fatalf("expected ErrInvalidPolynomialSize, got %v", err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+	polynomials, digests, points := randomClaims(t)
+	proof, err := BatchOpen(polynomials, digests, points, sha256.New(), testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := proof.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _proof OpeningProof
+	read, err := _proof.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatalf("read %d bytes, wrote %d", read, written)
+	}
+	if !reflect.DeepEqual(proof, _proof) {
+		t.Fatal("opening proof serialization failed")
+	}
+}
+
+func BenchmarkBatchOpen(b *testing.B) {
+	const size = 1 << 10
+	srs, err := kzg.NewSRS(size, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var zeta, omegaZeta fr.Element
+	zeta.SetRandom()
+	omegaZeta.SetUint64(7).Mul(&omegaZeta, &zeta)
+
+	const nbPolynomials = 10
+	polynomials := make([][]fr.Element, nbPolynomials)
+	digests := make([]kzg.Digest, nbPolynomials)
+	points := make([][]fr.Element, nbPolynomials)
+	for i := range polynomials {
+		polynomials[i] = make([]fr.Element, size)
+		for j := range polynomials[i] {
+			polynomials[i][j].SetRandom()
+		}
+		digests[i], _ = kzg.Commit(polynomials[i], srs)
+		points[i] = []fr.Element{zeta}
+		if i%3 == 0 {
+			points[i] = append(points[i], omegaZeta)
+		}
+	}
+
+	b.Run("open", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchOpen(polynomials, digests, points, sha256.New(), srs)
+		}
+	})
+	proof, _ := BatchOpen(polynomials, digests, points, sha256.New(), srs)
+	b.Run("verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerify(&proof, digests, points, sha256.New(), srs)
+		}
+	})
+}
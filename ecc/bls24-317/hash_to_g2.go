@@ -16,7 +16,7 @@ package bls24317
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls24-317/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fptower"
 )
 
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-4.1
@@ -18,7 +18,7 @@ package bls24317
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/fp"
-	"github.com/consensys/gnark-crypto/ecc/bls24-317/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fptower"
 )
 
 type batchOpG1Affine struct {
@@ -0,0 +1,226 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evm implements the BN254 precompiled contracts of the Ethereum
+// virtual machine: ecAdd (0x06) and ecMul (0x07) from EIP-196, and ecPairing
+// (0x08) from EIP-197.
+//
+// The functions take the raw call data and return the raw output, with the
+// same semantics as the precompiles: short inputs of ecAdd and ecMul are
+// right-padded with zeros, extra bytes are ignored, and points that are not on
+// the curve (or, in G2, not in the subgroup) are rejected with an error.
+//
+// # Encoding
+//
+//   - an element of 𝔽p is a 32-byte big-endian integer, strictly lower than p
+//   - a G1 point is x || y (64 bytes)
+//   - a G2 point is x.A1 || x.A0 || y.A1 || y.A0 (128 bytes, imaginary part first)
+//   - the point at infinity is encoded with all coordinates set to zero
+//
+// # See also
+//
+// https://eips.ethereum.org/EIPS/eip-196
+// https://eips.ethereum.org/EIPS/eip-197
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+const (
+	sizeFp      = fp.Bytes
+	sizeG1      = 2 * sizeFp
+	sizeG2      = 4 * sizeFp
+	sizeScalar  = 32
+	sizePairing = sizeG1 + sizeG2
+
+	// AddInputSize is the size of the ecAdd input, after padding
+	AddInputSize = 2 * sizeG1
+	// ScalarMulInputSize is the size of the ecMul input, after padding
+	ScalarMulInputSize = sizeG1 + sizeScalar
+	// PairingChunkSize is the size of one (G1, G2) pair in the ecPairing input
+	PairingChunkSize = sizePairing
+)
+
+var (
+	// ErrCoordinateOutOfRange is returned when a coordinate is not lower than the base field modulus
+	ErrCoordinateOutOfRange = errors.New("bn254: coordinate exceeds modulus")
+	// ErrMalformedPoint is returned when a point is not on the curve
+	ErrMalformedPoint = errors.New("bn254: malformed point")
+	// ErrNotInSubgroup is returned when a G2 point is not in the r-torsion subgroup
+	ErrNotInSubgroup = errors.New("bn254: point not in subgroup")
+	// ErrInvalidPairingInput is returned when the ecPairing input length is not a multiple of PairingChunkSize
+	ErrInvalidPairingInput = errors.New("bn254: invalid pairing input length")
+)
+
+// Add implements the ecAdd precompile (EIP-196).
+// It returns the 64-byte encoding of P + Q, where P and Q are read from input.
+func Add(input []byte) ([]byte, error) {
+	input = rightPad(input, AddInputSize)
+
+	var p, q bn254.G1Affine
+	if err := decodeG1(&p, input[:sizeG1]); err != nil {
+		return nil, err
+	}
+	if err := decodeG1(&q, input[sizeG1:2*sizeG1]); err != nil {
+		return nil, err
+	}
+
+	var res bn254.G1Jac
+	res.FromAffine(&p)
+	res.AddMixed(&q)
+	p.FromJacobian(&res)
+
+	return encodeG1(&p), nil
+}
+
+// ScalarMul implements the ecMul precompile (EIP-196).
+// It returns the 64-byte encoding of [s]P, where P and the 32-byte big-endian
+// scalar s are read from input. s is not required to be reduced.
+func ScalarMul(input []byte) ([]byte, error) {
+	input = rightPad(input, ScalarMulInputSize)
+
+	var p bn254.G1Affine
+	if err := decodeG1(&p, input[:sizeG1]); err != nil {
+		return nil, err
+	}
+	var s big.Int
+	s.SetBytes(input[sizeG1:ScalarMulInputSize])
+
+	p.ScalarMultiplication(&p, &s)
+
+	return encodeG1(&p), nil
+}
+
+// Pairing implements the ecPairing precompile (EIP-197).
+// The input is a concatenation of k (G1, G2) pairs; it returns 1 encoded on
+// 32 bytes if ∏ e(Pᵢ, Qᵢ) = 1 (in particular if k = 0), and 0 otherwise.
+func Pairing(input []byte) ([]byte, error) {
+	if len(input)%sizePairing != 0 {
+		return nil, ErrInvalidPairingInput
+	}
+	n := len(input) / sizePairing
+
+	P := make([]bn254.G1Affine, 0, n)
+	Q := make([]bn254.G2Affine, 0, n)
+	for i := 0; i < n; i++ {
+		chunk := input[i*sizePairing : (i+1)*sizePairing]
+		var p bn254.G1Affine
+		var q bn254.G2Affine
+		if err := decodeG1(&p, chunk[:sizeG1]); err != nil {
+			return nil, err
+		}
+		if err := decodeG2(&q, chunk[sizeG1:]); err != nil {
+			return nil, err
+		}
+		// pairs with a point at infinity don't contribute to the product
+		if p.IsInfinity() || q.IsInfinity() {
+			continue
+		}
+		P = append(P, p)
+		Q = append(Q, q)
+	}
+
+	res := make([]byte, 32)
+	if len(P) == 0 {
+		res[31] = 1
+		return res, nil
+	}
+	ok, err := bn254.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// rightPad returns buf, right-padded with zeros to size bytes, or truncated to size bytes.
+func rightPad(buf []byte, size int) []byte {
+	if len(buf) >= size {
+		return buf[:size]
+	}
+	res := make([]byte, size)
+	copy(res, buf)
+	return res
+}
+
+// decodeFp decodes a 32-byte big-endian element of 𝔽p, rejecting non-canonical values
+func decodeFp(z *fp.Element, buf []byte) error {
+	if err := z.SetBytesCanonical(buf[:sizeFp]); err != nil {
+		return ErrCoordinateOutOfRange
+	}
+	return nil
+}
+
+// decodeG1 decodes x || y into p and checks that p is on the curve.
+// (0, 0) is the point at infinity.
+func decodeG1(p *bn254.G1Affine, buf []byte) error {
+	if err := decodeFp(&p.X, buf[:sizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&p.Y, buf[sizeFp:sizeG1]); err != nil {
+		return err
+	}
+	if p.IsInfinity() {
+		return nil
+	}
+	if !p.IsOnCurve() {
+		return ErrMalformedPoint
+	}
+	// G1 is the full group E(𝔽p): no subgroup check needed
+	return nil
+}
+
+// decodeG2 decodes x.A1 || x.A0 || y.A1 || y.A0 into q and checks that q is
+// on the twist and in the r-torsion subgroup. (0, 0) is the point at infinity.
+func decodeG2(q *bn254.G2Affine, buf []byte) error {
+	if err := decodeFp(&q.X.A1, buf[:sizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&q.X.A0, buf[sizeFp:2*sizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&q.Y.A1, buf[2*sizeFp:3*sizeFp]); err != nil {
+		return err
+	}
+	if err := decodeFp(&q.Y.A0, buf[3*sizeFp:4*sizeFp]); err != nil {
+		return err
+	}
+	if q.IsInfinity() {
+		return nil
+	}
+	if !q.IsOnCurve() {
+		return ErrMalformedPoint
+	}
+	if !q.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// encodeG1 returns x || y, or 64 zero bytes if p is the point at infinity
+func encodeG1(p *bn254.G1Affine) []byte {
+	res := make([]byte, sizeG1)
+	x := p.X.Bytes()
+	y := p.Y.Bytes()
+	copy(res[:sizeFp], x[:])
+	copy(res[sizeFp:], y[:])
+	return res
+}
@@ -0,0 +1,247 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// precompileTest is a test vector in the go-ethereum format
+// (core/vm/testdata/precompiles)
+type precompileTest struct {
+	Input, Expected string
+	Name            string
+}
+
+func testVectors(t *testing.T, file string, f func([]byte) ([]byte, error)) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []precompileTest
+	if err := json.Unmarshal(buf, &tests); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		input, err := hex.DecodeString(test.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := f(input)
+		if err != nil {
+			t.Fatalf("%s: %v", test.Name, err)
+		}
+		if hex.EncodeToString(res) != test.Expected {
+			t.Fatalf("%s: expected %s, got %x", test.Name, test.Expected, res)
+		}
+	}
+}
+
+func TestAddVectors(t *testing.T) {
+	testVectors(t, "testdata/bn256Add.json", Add)
+}
+
+func TestScalarMulVectors(t *testing.T) {
+	testVectors(t, "testdata/bn256ScalarMul.json", ScalarMul)
+}
+
+func TestPairingVectors(t *testing.T) {
+	testVectors(t, "testdata/bn256Pairing.json", Pairing)
+}
+
+func TestConsistency(t *testing.T) {
+	_, _, g1, g2 := bn254.Generators()
+
+	var a, b fr.Element
+	a.SetRandom()
+	b.SetRandom()
+	var aBig, bBig big.Int
+	a.BigInt(&aBig)
+	b.BigInt(&bBig)
+
+	var p, q, pq bn254.G1Affine
+	p.ScalarMultiplication(&g1, &aBig)
+	q.ScalarMultiplication(&g1, &bBig)
+	pq.Add(&p, &q)
+
+	// ecAdd
+	res, err := Add(append(encodeG1(&p), encodeG1(&q)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res, encodeG1(&pq)) {
+		t.Fatal("ecAdd doesn't match G1Affine.Add")
+	}
+
+	// ecMul
+	var s [sizeScalar]byte
+	bBig.FillBytes(s[:])
+	res, err = ScalarMul(append(encodeG1(&p), s[:]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pb bn254.G1Affine
+	pb.ScalarMultiplication(&p, &bBig)
+	if !bytes.Equal(res, encodeG1(&pb)) {
+		t.Fatal("ecMul doesn't match G1Affine.ScalarMultiplication")
+	}
+
+	// ecPairing: e(aG₁, G₂)⋅e(-G₁, aG₂) == 1
+	var ag2 bn254.G2Affine
+	ag2.ScalarMultiplication(&g2, &aBig)
+	var negG1 bn254.G1Affine
+	negG1.Neg(&g1)
+	input := append(encodeG1(&p), encodeG2(&g2)...)
+	input = append(input, encodeG1(&negG1)...)
+	input = append(input, encodeG2(&ag2)...)
+	res, err = Pairing(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res[31] != 1 {
+		t.Fatal("pairing check should succeed")
+	}
+	// e(aG₁, G₂)⋅e(G₁, aG₂) != 1
+	input = append(encodeG1(&p), encodeG2(&g2)...)
+	input = append(input, encodeG1(&g1)...)
+	input = append(input, encodeG2(&ag2)...)
+	res, err = Pairing(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res[31] != 0 {
+		t.Fatal("pairing check should fail")
+	}
+}
+
+func TestPadding(t *testing.T) {
+	_, _, g1, _ := bn254.Generators()
+
+	// ecMul with a short scalar is right-padded: 0x01 → 2²⁴⁸
+	input := append(encodeG1(&g1), 0x01)
+	res, err := ScalarMul(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p bn254.G1Affine
+	p.ScalarMultiplication(&g1, new(big.Int).Lsh(big.NewInt(1), 248))
+	if !bytes.Equal(res, encodeG1(&p)) {
+		t.Fatal("ecMul input should be right-padded")
+	}
+
+	// ecAdd with a single point
+	res, err = Add(encodeG1(&g1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res, encodeG1(&g1)) {
+		t.Fatal("ecAdd input should be right-padded")
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	_, _, g1, g2 := bn254.Generators()
+
+	// coordinate ≥ p
+	modulus := make([]byte, sizeFp)
+	fp.Modulus().FillBytes(modulus)
+	input := append(modulus, make([]byte, sizeFp)...)
+	if _, err := Add(input); err != ErrCoordinateOutOfRange {
+		t.Fatal("expected ErrCoordinateOutOfRange, got", err)
+	}
+	if _, err := ScalarMul(input); err != ErrCoordinateOutOfRange {
+		t.Fatal("expected ErrCoordinateOutOfRange, got", err)
+	}
+
+	// G1 point not on the curve
+	input = encodeG1(&g1)
+	input[sizeG1-1] ^= 1
+	if _, err := Add(input); err != ErrMalformedPoint {
+		t.Fatal("expected ErrMalformedPoint, got", err)
+	}
+	if _, err := ScalarMul(input); err != ErrMalformedPoint {
+		t.Fatal("expected ErrMalformedPoint, got", err)
+	}
+	if _, err := Pairing(append(input, encodeG2(&g2)...)); err != ErrMalformedPoint {
+		t.Fatal("expected ErrMalformedPoint, got", err)
+	}
+
+	// G2 point not on the twist
+	input = encodeG2(&g2)
+	input[sizeG2-1] ^= 1
+	if _, err := Pairing(append(encodeG1(&g1), input...)); err != ErrMalformedPoint {
+		t.Fatal("expected ErrMalformedPoint, got", err)
+	}
+
+	// G2 point on the twist, but not in the r-torsion
+	var q bn254.G2Affine
+	var bTwist, rhs fptower.E2
+	var three fp.Element
+	three.SetUint64(3)
+	bTwist.SetString("9", "1").Inverse(&bTwist).MulByElement(&bTwist, &three)
+	q.X.SetOne()
+	for {
+		rhs.Square(&q.X).Mul(&rhs, &q.X).Add(&rhs, &bTwist)
+		if rhs.Legendre() == 1 {
+			break
+		}
+		q.X.A0.Add(&q.X.A0, &three)
+	}
+	q.Y.Sqrt(&rhs)
+	if !q.IsOnCurve() || q.IsInSubGroup() {
+		t.Fatal("test point should be on the twist, outside of G2")
+	}
+	if _, err := Pairing(append(encodeG1(&g1), encodeG2(&q)...)); err != ErrNotInSubgroup {
+		t.Fatal("expected ErrNotInSubgroup, got", err)
+	}
+
+	// pairing input length
+	if _, err := Pairing(make([]byte, sizePairing+1)); err != ErrInvalidPairingInput {
+		t.Fatal("expected ErrInvalidPairingInput, got", err)
+	}
+}
+
+// encodeG2 returns x.A1 || x.A0 || y.A1 || y.A0
+func encodeG2(q *bn254.G2Affine) []byte {
+	res := make([]byte, 0, sizeG2)
+	for _, e := range []*fp.Element{&q.X.A1, &q.X.A0, &q.Y.A1, &q.Y.A0} {
+		b := e.Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
+
+func BenchmarkPairing(b *testing.B) {
+	input, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c2" +
+		"1800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed" +
+		"090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b" +
+		"12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Pairing(input)
+	}
+}
@@ -825,6 +825,51 @@ func BatchDecompressTorus(x []E6) ([]E12, error) {
 	return res, nil
 }
 
+// SizeOfGTCompressed represents the size in bytes that a compressed GT element need in binary form
+const SizeOfGTCompressed = SizeOfGT / 2
+
+// CompressedBytes returns the torus-based compressed binary encoding of z ∈ GT,
+// half the size of Bytes: y = (1+z.C0)/z.C1 (see CompressTorus) is encoded
+// as the z.C0 half of Bytes. The identity is encoded as y = 0, which otherwise
+// corresponds to -1 ∉ GT.
+//
+// It returns an error if z.C1 = 0 and z ≠ 1, i.e. if z = -1.
+func (z *E12) CompressedBytes() (r [SizeOfGTCompressed]byte, err error) {
+	var y E12
+	if !z.IsOne() {
+		if y.C0, err = z.CompressTorus(); err != nil {
+			return
+		}
+	}
+	b := y.Bytes()
+	copy(r[:], b[SizeOfGTCompressed:])
+	return
+}
+
+// SetCompressedBytes sets z to the GT element encoded in e by CompressedBytes.
+// It returns an error if the decompressed element is not in GT.
+func (z *E12) SetCompressedBytes(e []byte) error {
+	if len(e) != SizeOfGTCompressed {
+		return errors.New("invalid buffer size")
+	}
+	var b [SizeOfGT]byte
+	copy(b[SizeOfGTCompressed:], e)
+	var y E12
+	if err := y.SetBytes(b[:]); err != nil {
+		return err
+	}
+	if y.C0.IsZero() {
+		z.SetOne()
+		return nil
+	}
+	res := y.C0.DecompressTorus()
+	if !res.IsInSubGroup() {
+		return errors.New("invalid compressed GT element: not in the subgroup")
+	}
+	z.Set(&res)
+	return nil
+}
+
 func (z *E12) Select(cond int, caseZ *E12, caseNz *E12) *E12 {
 	//Might be able to save a nanosecond or two by an aggregate implementation
 
@@ -0,0 +1,253 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidPtau = errors.New("invalid ptau file")
+	ErrSRSTooSmall = errors.New("srs has fewer powers than requested")
+)
+
+// sections of a ptau file used to build the SRS
+const (
+	ptauSectionHeader = 1
+	ptauSectionTauG1  = 2
+	ptauSectionTauG2  = 3
+)
+
+// ptauBatchSize is the number of points read at once from a ptau file
+const ptauBatchSize = 1 << 12
+
+// NewSRSFromPtau reads a powers of tau file in the snarkjs .ptau format (such as the
+// ones of the Hermez ceremony) and returns the SRS made of its first size powers of τ
+// in G1, or all of them if size is 0.
+//
+// The points are checked to be in the subgroups, and the SRS to be made of the
+// successive powers of the τ of [τ]G₂ (see CheckSRS).
+func NewSRSFromPtau(r io.Reader, size uint64) (*SRS, error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, err
+	}
+	if string(magic[:]) != "ptau" {
+		return nil, ErrInvalidPtau
+	}
+	var version, nbSections uint32
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(br, binary.LittleEndian, &nbSections); err != nil {
+		return nil, err
+	}
+	if version != 1 {
+		return nil, ErrInvalidPtau
+	}
+
+	var srs SRS
+	var power uint32
+	var hasHeader, hasG1, hasG2 bool
+	for s := uint32(0); s < nbSections && !(hasG1 && hasG2); s++ {
+		var sectionType uint32
+		var sectionSize uint64
+		if err := binary.Read(br, binary.LittleEndian, &sectionType); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(br, binary.LittleEndian, &sectionSize); err != nil {
+			return nil, err
+		}
+		section := io.LimitReader(br, int64(sectionSize))
+
+		switch sectionType {
+		case ptauSectionHeader:
+			var err error
+			if power, err = readPtauHeader(section); err != nil {
+				return nil, err
+			}
+			hasHeader = true
+		case ptauSectionTauG1:
+			if !hasHeader {
+				return nil, ErrInvalidPtau
+			}
+			// [τⁱ]G₁ for i < 2^(power+1)-1
+			nbPowers := uint64(1)<<(power+1) - 1
+			if sectionSize != nbPowers*bn254.SizeOfG1AffineUncompressed {
+				return nil, ErrInvalidPtau
+			}
+			if size == 0 {
+				size = nbPowers
+			}
+			if size > nbPowers {
+				return nil, ErrSRSTooSmall
+			}
+			srs.G1 = make([]bn254.G1Affine, size)
+			if err := readPtauG1(section, srs.G1); err != nil {
+				return nil, err
+			}
+			hasG1 = true
+		case ptauSectionTauG2:
+			if !hasHeader {
+				return nil, ErrInvalidPtau
+			}
+			// [τⁱ]G₂ for i < 2^power
+			if sectionSize != (uint64(1)<<power)*bn254.SizeOfG2AffineUncompressed {
+				return nil, ErrInvalidPtau
+			}
+			if err := readPtauG2(section, srs.G2[:]); err != nil {
+				return nil, err
+			}
+			hasG2 = true
+		}
+
+		// skip the rest of the section
+		if _, err := io.Copy(io.Discard, section); err != nil {
+			return nil, err
+		}
+	}
+	if !hasG1 || !hasG2 {
+		return nil, ErrInvalidPtau
+	}
+
+	if err := CheckSRS(&srs); err != nil {
+		return nil, err
+	}
+
+	return &srs, nil
+}
+
+// readPtauHeader reads the header section of a ptau file and returns the power of
+// the ceremony, the file holding 2^(power+1)-1 powers of τ in G₁
+func readPtauHeader(r io.Reader) (uint32, error) {
+	var n8 uint32
+	if err := binary.Read(r, binary.LittleEndian, &n8); err != nil {
+		return 0, err
+	}
+	if n8 != fp.Bytes {
+		return 0, ErrInvalidPtau
+	}
+
+	// base field modulus, in little endian
+	q := make([]byte, n8)
+	if _, err := io.ReadFull(r, q); err != nil {
+		return 0, err
+	}
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	if new(big.Int).SetBytes(q).Cmp(fp.Modulus()) != 0 {
+		return 0, ErrInvalidPtau
+	}
+
+	var power, ceremonyPower uint32
+	if err := binary.Read(r, binary.LittleEndian, &power); err != nil {
+		return 0, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &ceremonyPower); err != nil {
+		return 0, err
+	}
+	if power > 31 {
+		return 0, ErrInvalidPtau
+	}
+
+	return power, nil
+}
+
+// setPtauCoordinate sets z from the little endian Montgomery form of a coordinate in
+// a ptau file. snarkjs uses the same Montgomery constant R = 2^(64⋅fp.Limbs) as fp.Element.
+func setPtauCoordinate(z *fp.Element, b []byte) error {
+	var be [fp.Bytes]byte
+	for i := range be {
+		be[i] = b[fp.Bytes-1-i]
+	}
+	// check that the coordinate is reduced
+	if err := z.SetBytesCanonical(be[:]); err != nil {
+		return err
+	}
+	for i := range z {
+		z[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return nil
+}
+
+// readPtauG1 reads len(points) points of G₁ from a ptau section
+func readPtauG1(r io.Reader, points []bn254.G1Affine) error {
+	const size = bn254.SizeOfG1AffineUncompressed
+	buf := make([]byte, ptauBatchSize*size)
+	for start := 0; start < len(points); start += ptauBatchSize {
+		batch := points[start:]
+		if len(batch) > ptauBatchSize {
+			batch = batch[:ptauBatchSize]
+		}
+		if _, err := io.ReadFull(r, buf[:len(batch)*size]); err != nil {
+			return err
+		}
+
+		errs := make([]error, len(batch))
+		parallel.Execute(len(batch), func(start, end int) {
+			for i := start; i < end; i++ {
+				b := buf[i*size:]
+				if errs[i] = setPtauCoordinate(&batch[i].X, b); errs[i] != nil {
+					continue
+				}
+				if errs[i] = setPtauCoordinate(&batch[i].Y, b[fp.Bytes:]); errs[i] != nil {
+					continue
+				}
+				if !batch[i].IsInSubGroup() {
+					errs[i] = ErrInvalidPtau
+				}
+			}
+		})
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readPtauG2 reads len(points) points of G₂ from a ptau section
+func readPtauG2(r io.Reader, points []bn254.G2Affine) error {
+	buf := make([]byte, bn254.SizeOfG2AffineUncompressed)
+	for i := range points {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		coordinates := []*fp.Element{&points[i].X.A0, &points[i].X.A1, &points[i].Y.A0, &points[i].Y.A1}
+		for j, z := range coordinates {
+			if err := setPtauCoordinate(z, buf[j*fp.Bytes:]); err != nil {
+				return err
+			}
+		}
+		if !points[i].IsInSubGroup() {
+			return ErrInvalidPtau
+		}
+	}
+	return nil
+}
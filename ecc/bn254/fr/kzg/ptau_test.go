@@ -0,0 +1,221 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// writePtau writes the powers of τ of a ceremony of the given power in the snarkjs .ptau format,
+// from the powers of srs which must have at least 2^(power+1)-1 of them.
+func writePtau(w io.Writer, srs *SRS, tau *big.Int, power uint32) error {
+	coordinate := func(z *fp.Element) []byte {
+		// little endian Montgomery form
+		var b [fp.Bytes]byte
+		for i := range z {
+			binary.LittleEndian.PutUint64(b[8*i:], z[i])
+		}
+		return b[:]
+	}
+	var buf bytes.Buffer
+	writeSection := func(sectionType uint32, data []byte) {
+		binary.Write(&buf, binary.LittleEndian, sectionType)
+		binary.Write(&buf, binary.LittleEndian, uint64(len(data)))
+		buf.Write(data)
+	}
+
+	buf.WriteString("ptau")
+	binary.Write(&buf, binary.LittleEndian, uint32(1))
+	binary.Write(&buf, binary.LittleEndian, uint32(5))
+
+	// header
+	var header bytes.Buffer
+	binary.Write(&header, binary.LittleEndian, uint32(fp.Bytes))
+	q := fp.Modulus().FillBytes(make([]byte, fp.Bytes))
+	for i := len(q) - 1; i >= 0; i-- {
+		header.WriteByte(q[i])
+	}
+	binary.Write(&header, binary.LittleEndian, power)
+	binary.Write(&header, binary.LittleEndian, power)
+	writeSection(1, header.Bytes())
+
+	// a section that is not used
+	writeSection(7, []byte("contributions"))
+
+	var g1 bytes.Buffer
+	for i := 0; i < 1<<(power+1)-1; i++ {
+		g1.Write(coordinate(&srs.G1[i].X))
+		g1.Write(coordinate(&srs.G1[i].Y))
+	}
+	writeSection(2, g1.Bytes())
+
+	var g2 bytes.Buffer
+	var p bn254.G2Affine
+	p.Set(&srs.G2[0])
+	for i := 0; i < 1<<power; i++ {
+		g2.Write(coordinate(&p.X.A0))
+		g2.Write(coordinate(&p.X.A1))
+		g2.Write(coordinate(&p.Y.A0))
+		g2.Write(coordinate(&p.Y.A1))
+		p.ScalarMultiplication(&p, tau)
+	}
+	writeSection(3, g2.Bytes())
+
+	// [ατⁱ]G₁, not used
+	writeSection(4, make([]byte, (1<<power)*bn254.SizeOfG1AffineUncompressed))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func TestPtau(t *testing.T) {
+	const power = 3
+	const nbPowers = 1<<(power+1) - 1
+	tau := big.NewInt(1789)
+	srs, err := NewSRS(nbPowers, tau)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	ptauFile := func(name string, srs *SRS) string {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := writePtau(f, srs, tau, power); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	newSRSFromPtau := func(path string, size uint64) (*SRS, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return NewSRSFromPtau(f, size)
+	}
+	valid := ptauFile("valid.ptau", srs)
+
+	for _, size := range []uint64{0, 2, 5, nbPowers} {
+		imported, err := newSRSFromPtau(valid, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedSize := size
+		if size == 0 {
+			expectedSize = nbPowers
+		}
+		if uint64(len(imported.G1)) != expectedSize {
+			t.Fatalf("expected %d powers, got %d", expectedSize, len(imported.G1))
+		}
+		for i := range imported.G1 {
+			if !imported.G1[i].Equal(&srs.G1[i]) {
+				t.Fatalf("wrong power %d in G1", i)
+			}
+		}
+		if !imported.G2[0].Equal(&srs.G2[0]) || !imported.G2[1].Equal(&srs.G2[1]) {
+			t.Fatal("wrong powers in G2")
+		}
+	}
+
+	if _, err := newSRSFromPtau(valid, nbPowers+1); !errors.Is(err, ErrSRSTooSmall) {
+		t.Fatalf("expected ErrSRSTooSmall, got %v", err)
+	}
+
+	// a power of another τ
+	tampered := *srs
+	tampered.G1 = append([]bn254.G1Affine{}, srs.G1...)
+	tampered.G1[5].Add(&tampered.G1[5], &srs.G1[0])
+	if _, err := newSRSFromPtau(ptauFile("tampered.ptau", &tampered), 0); !errors.Is(err, ErrInconsistentSRS) {
+		t.Fatalf("expected ErrInconsistentSRS, got %v", err)
+	}
+	// but the first powers are still consistent
+	if _, err := newSRSFromPtau(ptauFile("tampered.ptau", &tampered), 5); err != nil {
+		t.Fatal(err)
+	}
+
+	// invalid files
+	content, err := os.ReadFile(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := append([]byte{}, content...)
+	invalid[0] = 'P'
+	if _, err := NewSRSFromPtau(bytes.NewReader(invalid), 0); !errors.Is(err, ErrInvalidPtau) {
+		t.Fatalf("wrong magic: expected ErrInvalidPtau, got %v", err)
+	}
+	// modulus in the header
+	invalid = append([]byte{}, content...)
+	invalid[4+4+4+4+8+4]++
+	if _, err := NewSRSFromPtau(bytes.NewReader(invalid), 0); !errors.Is(err, ErrInvalidPtau) {
+		t.Fatalf("wrong modulus: expected ErrInvalidPtau, got %v", err)
+	}
+	if _, err := NewSRSFromPtau(bytes.NewReader(content[:len(content)/2]), 0); err == nil {
+		t.Fatal("truncated file should be rejected")
+	}
+}
+
+// snarkjsPtau is a ceremony of power 2 with one contribution and a random beacon, produced by
+// snarkjs itself:
+//
+//	snarkjs powersoftau new bn128 2 pot2_0000.ptau
+//	snarkjs powersoftau contribute pot2_0000.ptau pot2_0001.ptau -e="some entropy"
+//	snarkjs powersoftau beacon pot2_0001.ptau pot2_final.ptau 0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f 10
+const snarkjsPtau = "testdata/pot2_final.ptau"
+
+func TestPtauSnarkjs(t *testing.T) {
+	f, err := os.Open(snarkjsPtau)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("no " + snarkjsPtau + ": compatibility with the files of snarkjs is NOT checked")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	srs, err := NewSRSFromPtau(f, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srs.G1) != 1<<3-1 {
+		t.Fatalf("expected %d powers, got %d", 1<<3-1, len(srs.G1))
+	}
+	_, _, g1, g2 := bn254.Generators()
+	if !srs.G1[0].Equal(&g1) || !srs.G2[0].Equal(&g2) {
+		t.Fatal("the powers should start with the generators")
+	}
+	if srs.G1[1].Equal(&g1) || srs.G2[1].Equal(&g2) {
+		t.Fatal("τ should not be 1 after a contribution")
+	}
+	if err := CheckSRS(srs); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -18,7 +18,7 @@ package bn254
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
-	"github.com/consensys/gnark-crypto/ecc/bn254/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fptower"
 )
 
 // mapToCurve2 implements the Shallue and van de Woestijne method, applicable to any elliptic curve in Weierstrass form
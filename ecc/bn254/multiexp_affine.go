@@ -18,7 +18,7 @@ package bn254
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
-	"github.com/consensys/gnark-crypto/ecc/bn254/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fptower"
 )
 
 type batchOpG1Affine struct {
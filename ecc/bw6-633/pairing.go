@@ -18,7 +18,7 @@ import (
 	"errors"
 
 	"github.com/consensys/gnark-crypto/ecc/bw6-633/fp"
-	"github.com/consensys/gnark-crypto/ecc/bw6-633/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bw6-633/fptower"
 )
 
 // GT target group of the pairing
@@ -268,6 +268,19 @@ func (p *PointAffine) ScalarMultiplication(p1 *PointAffine, scalar *big.Int) *Po
 	return p
 }
 
+// ScalarMultiplicationCT scalar multiplication of a point
+// p1 in affine coordinates with a scalar in big.Int, in constant time
+// with respect to the scalar. See PointProj.ScalarMultiplicationCT
+func (p *PointAffine) ScalarMultiplicationCT(p1 *PointAffine, scalar *big.Int) *PointAffine {
+
+	var p1Proj, resProj PointProj
+	p1Proj.FromAffine(p1)
+	resProj.ScalarMultiplicationCT(&p1Proj, scalar)
+	p.FromProj(&resProj)
+
+	return p
+}
+
 // setInfinity sets p to O (0:1)
 func (p *PointAffine) setInfinity() *PointAffine {
 	p.X.SetZero()
@@ -439,6 +452,57 @@ func (p *PointProj) ScalarMultiplication(p1 *PointProj, scalar *big.Int) *PointP
 	return p
 }
 
+// ScalarMultiplicationCT scalar multiplication of a point
+// p1 in projective coordinates with a scalar in big.Int, in constant time
+// with respect to the scalar. It should be used instead of ScalarMultiplication
+// when the scalar is secret.
+//
+// The scalar is reduced modulo the order of the prime subgroup, so p1 is
+// expected to be in this subgroup. It uses a fixed 4-bits window, table
+// lookups that read all the entries and the complete addition formula.
+func (p *PointProj) ScalarMultiplicationCT(p1 *PointProj, scalar *big.Int) *PointProj {
+	const (
+		windowSize = 4
+		tableSize  = 1 << windowSize
+	)
+
+	ecurve := GetEdwardsCurve()
+
+	var _scalar big.Int
+	_scalar.Mod(scalar, &ecurve.Order)
+	var b [fr.Bytes]byte
+	_scalar.FillBytes(b[:])
+
+	// table[i] = i ⋅ p1
+	var table [tableSize]PointProj
+	table[0].setInfinity()
+	table[1].Set(p1)
+	for i := 2; i < tableSize; i++ {
+		table[i].Add(&table[i-1], p1)
+	}
+
+	var resProj, t PointProj
+	resProj.setInfinity()
+	for i := range b {
+		for _, w := range [2]uint8{b[i] >> windowSize, b[i] & (tableSize - 1)} {
+			for j := 0; j < windowSize; j++ {
+				resProj.Double(&resProj)
+			}
+			// t = table[w], reading all the entries
+			for k := range table {
+				c := subtle.ConstantTimeByteEq(w, uint8(k))
+				t.X.Select(c, &t.X, &table[k].X)
+				t.Y.Select(c, &t.Y, &table[k].Y)
+				t.Z.Select(c, &t.Z, &table[k].Z)
+			}
+			resProj.Add(&resProj, &t)
+		}
+	}
+
+	p.Set(&resProj)
+	return p
+}
+
 // ------- Extended coordinates
 
 // Set sets p to p1 and return it
@@ -18,7 +18,7 @@ import (
 	"errors"
 
 	"github.com/consensys/gnark-crypto/ecc/bw6-756/fp"
-	"github.com/consensys/gnark-crypto/ecc/bw6-756/internal/fptower"
+	"github.com/consensys/gnark-crypto/ecc/bw6-756/fptower"
 )
 
 // GT target group of the pairing
@@ -98,7 +98,7 @@ func GenerateKey(r io.Reader) (*PrivateKey, error) {
 
 	var bScalar big.Int
 	bScalar.SetBytes(priv.scalar[:])
-	pub.A.ScalarMultiplication(&c.Base, &bScalar)
+	pub.A.ScalarMultiplicationCT(&c.Base, &bScalar)
 
 	priv.PublicKey = pub
 
@@ -146,7 +146,7 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 	blindingFactorBigInt.SetBytes(blindingFactorBytes[:sizeFr])
 
 	// compute R = randScalar*Base
-	res.R.ScalarMultiplication(&curveParams.Base, &blindingFactorBigInt)
+	res.R.ScalarMultiplicationCT(&curveParams.Base, &blindingFactorBigInt)
 	if !res.R.IsOnCurve() {
 		return nil, errNotOnCurve
 	}
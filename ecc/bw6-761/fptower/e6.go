@@ -771,3 +771,52 @@ func BatchDecompressTorus(x []E3) ([]E6, error) {
 
 	return res, nil
 }
+
+// SizeOfGTCompressed represents the size in bytes that a compressed GT element need in binary form
+const SizeOfGTCompressed = SizeOfGT / 2
+
+// CompressedBytes returns the torus-based compressed binary encoding of z ∈ GT,
+// half the size of Bytes: y = (1+z.B0)/z.B1 (see CompressTorus) is encoded
+// as the z.B0 half of Bytes. The identity is encoded as y = 0, which otherwise
+// corresponds to -1 ∉ GT.
+//
+// It returns an error if z.B1 = 0 and z ≠ 1, i.e. if z = -1.
+func (z *E6) CompressedBytes() (r [SizeOfGTCompressed]byte, err error) {
+	var y E6
+	if !z.IsOne() {
+		if y.B0, err = z.CompressTorus(); err != nil {
+			return
+		}
+	}
+	b := y.Bytes()
+	copy(r[:], b[SizeOfGTCompressed:])
+	return
+}
+
+// SetCompressedBytes sets z to the GT element encoded in e by CompressedBytes.
+// It returns an error if the decompressed element is not in GT.
+func (z *E6) SetCompressedBytes(e []byte) error {
+	if len(e) != SizeOfGTCompressed {
+		return errors.New("invalid buffer size")
+	}
+	var b [SizeOfGT]byte
+	copy(b[SizeOfGTCompressed:], e)
+	var y E6
+	if err := y.SetBytes(b[:]); err != nil {
+		return err
+	}
+	// SetBytes doesn't check that the coordinates are canonical
+	if y.Bytes() != b {
+		return errors.New("invalid compressed GT element: non canonical coordinates")
+	}
+	if y.B0.IsZero() {
+		z.SetOne()
+		return nil
+	}
+	res := y.B0.DecompressTorus()
+	if !res.IsInSubGroup() {
+		return errors.New("invalid compressed GT element: not in the subgroup")
+	}
+	z.Set(&res)
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package shplonk provides batch openings of KZG commitments of several polynomials,
+// each at its own set of points, following [BDFG20] (Shplonk).
+//
+// The proof is made of two G1 points and of the claimed values, and its verification
+// costs a single pairing check, whatever the number of polynomials and points.
+//
+// [BDFG20]: https://eprint.iacr.org/2020/081.pdf
+package shplonk
@@ -287,11 +287,11 @@ func TestCrossMultiExpG1(t *testing.T) {
 
 // _innerMsmG1Reference always do ext jacobian with c == 16
 func _innerMsmG1Reference(p *G1Jac, points []G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G1Jac {
-	// partition the scalars
-	digits, _ := partitionScalars(scalars, 16, config.NbTasks)
-
 	nbChunks := computeNbChunks(16)
 
+	// partition the scalars
+	digits, _ := partitionScalars(scalars, 16, nbChunks, config.NbTasks)
+
 	// for each chunk, spawn one go routine that'll loop through all the scalars in the
 	// corresponding bit-window
 	// note that buckets is an array allocated on the stack and this is critical for performance
@@ -312,6 +312,60 @@ func _innerMsmG1Reference(p *G1Jac, points []G1Affine, scalars []fr.Element, con
 	return msmReduceChunkG1Affine(p, int(16), chChunks[:])
 }
 
+func TestMultiExpSmallScalarsG1(t *testing.T) {
+	t.Parallel()
+	// enough points to split the multiExp of small scalars
+	const nbSamples = 2100
+
+	var samplePoints [nbSamples]G1Affine
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	samplePoints[7].setInfinity()
+
+	var sampleScalars [nbSamples]fr.Element
+	var s big.Int
+	for _, nbBits := range []int{1, 2, 8, 13, 32, 64, 100} {
+		bound := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+		for i := range sampleScalars {
+			s.Rand(rand.New(rand.NewSource(int64(i*nbBits))), bound)
+			sampleScalars[i].SetBigInt(&s)
+		}
+		// sparse scalars
+		for i := 0; i < nbSamples; i += 3 {
+			sampleScalars[i].SetZero()
+		}
+
+		var expected G1Jac
+		expected.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{})
+
+		for _, nbTasks := range []int{1, 5, 64, 0} {
+			for _, maxBits := range []int{nbBits, ecc.ScalarsMaxBitsAuto} {
+				var res G1Jac
+				if _, err := res.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{NbTasks: nbTasks, ScalarsMaxBits: maxBits}); err != nil {
+					t.Fatal(err)
+				}
+				if !res.Equal(&expected) {
+					t.Fatalf("%d-bit scalars, %d tasks, ScalarsMaxBits %d: wrong result", nbBits, nbTasks, maxBits)
+				}
+			}
+		}
+	}
+
+	// all scalars zero
+	for i := range sampleScalars {
+		sampleScalars[i].SetZero()
+	}
+	var res G1Jac
+	res.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{ScalarsMaxBits: ecc.ScalarsMaxBitsAuto})
+	if !res.Z.IsZero() {
+		t.Fatal("multiExp of zero scalars should be infinity")
+	}
+}
+
 func BenchmarkMultiExpG1(b *testing.B) {
 
 	const (
@@ -377,6 +431,35 @@ func BenchmarkMultiExpG1(b *testing.B) {
 	}
 }
 
+func BenchmarkMultiExpSmallScalarsG1(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	var (
+		samplePoints  [nbSamples]G1Affine
+		sampleScalars [nbSamples]fr.Element
+	)
+	fillBenchBasesG1(samplePoints[:])
+
+	var testPoint G1Affine
+	for _, nbBits := range []int{1, 8, 32} {
+		for i := range sampleScalars {
+			sampleScalars[i].SetUint64(rand.Uint64() >> (64 - nbBits))
+		}
+		b.Run(fmt.Sprintf("%d-bit scalars", nbBits), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{})
+			}
+		})
+		b.Run(fmt.Sprintf("%d-bit scalars-ScalarsMaxBits", nbBits), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{ScalarsMaxBits: ecc.ScalarsMaxBitsAuto})
+			}
+		})
+	}
+}
+
 func BenchmarkMultiExpG1Reference(b *testing.B) {
 	const nbSamples = 1 << 20
 
@@ -699,11 +782,11 @@ func TestCrossMultiExpG2(t *testing.T) {
 
 // _innerMsmG2Reference always do ext jacobian with c == 16
 func _innerMsmG2Reference(p *G2Jac, points []G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) *G2Jac {
-	// partition the scalars
-	digits, _ := partitionScalars(scalars, 16, config.NbTasks)
-
 	nbChunks := computeNbChunks(16)
 
+	// partition the scalars
+	digits, _ := partitionScalars(scalars, 16, nbChunks, config.NbTasks)
+
 	// for each chunk, spawn one go routine that'll loop through all the scalars in the
 	// corresponding bit-window
 	// note that buckets is an array allocated on the stack and this is critical for performance
@@ -724,6 +807,60 @@ func _innerMsmG2Reference(p *G2Jac, points []G2Affine, scalars []fr.Element, con
 	return msmReduceChunkG2Affine(p, int(16), chChunks[:])
 }
 
+func TestMultiExpSmallScalarsG2(t *testing.T) {
+	t.Parallel()
+	// enough points to split the multiExp of small scalars
+	const nbSamples = 2100
+
+	var samplePoints [nbSamples]G2Affine
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	samplePoints[7].setInfinity()
+
+	var sampleScalars [nbSamples]fr.Element
+	var s big.Int
+	for _, nbBits := range []int{1, 2, 8, 13, 32, 64, 100} {
+		bound := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+		for i := range sampleScalars {
+			s.Rand(rand.New(rand.NewSource(int64(i*nbBits))), bound)
+			sampleScalars[i].SetBigInt(&s)
+		}
+		// sparse scalars
+		for i := 0; i < nbSamples; i += 3 {
+			sampleScalars[i].SetZero()
+		}
+
+		var expected G2Jac
+		expected.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{})
+
+		for _, nbTasks := range []int{1, 5, 64, 0} {
+			for _, maxBits := range []int{nbBits, ecc.ScalarsMaxBitsAuto} {
+				var res G2Jac
+				if _, err := res.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{NbTasks: nbTasks, ScalarsMaxBits: maxBits}); err != nil {
+					t.Fatal(err)
+				}
+				if !res.Equal(&expected) {
+					t.Fatalf("%d-bit scalars, %d tasks, ScalarsMaxBits %d: wrong result", nbBits, nbTasks, maxBits)
+				}
+			}
+		}
+	}
+
+	// all scalars zero
+	for i := range sampleScalars {
+		sampleScalars[i].SetZero()
+	}
+	var res G2Jac
+	res.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{ScalarsMaxBits: ecc.ScalarsMaxBitsAuto})
+	if !res.Z.IsZero() {
+		t.Fatal("multiExp of zero scalars should be infinity")
+	}
+}
+
 func BenchmarkMultiExpG2(b *testing.B) {
 
 	const (
@@ -789,6 +926,35 @@ func BenchmarkMultiExpG2(b *testing.B) {
 	}
 }
 
+func BenchmarkMultiExpSmallScalarsG2(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	var (
+		samplePoints  [nbSamples]G2Affine
+		sampleScalars [nbSamples]fr.Element
+	)
+	fillBenchBasesG2(samplePoints[:])
+
+	var testPoint G2Affine
+	for _, nbBits := range []int{1, 8, 32} {
+		for i := range sampleScalars {
+			sampleScalars[i].SetUint64(rand.Uint64() >> (64 - nbBits))
+		}
+		b.Run(fmt.Sprintf("%d-bit scalars", nbBits), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{})
+			}
+		})
+		b.Run(fmt.Sprintf("%d-bit scalars-ScalarsMaxBits", nbBits), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:], sampleScalars[:], ecc.MultiExpConfig{ScalarsMaxBits: ecc.ScalarsMaxBitsAuto})
+			}
+		})
+	}
+}
+
 func BenchmarkMultiExpG2Reference(b *testing.B) {
 	const nbSamples = 1 << 20
 
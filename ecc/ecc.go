@@ -49,11 +49,12 @@ const (
 	BW6_633
 	BW6_756
 	SECP256K1
+	P256
 )
 
 // Implemented return the list of curves fully implemented in gnark-crypto
 func Implemented() []ID {
-	return []ID{BN254, BLS12_377, BLS12_381, BW6_761, BLS24_315, BW6_633, BLS12_378, BW6_756, BLS24_317, SECP256K1}
+	return []ID{BN254, BLS12_377, BLS12_381, BW6_761, BLS24_315, BW6_633, BLS12_378, BW6_756, BLS24_317, SECP256K1, P256}
 }
 
 func (id ID) String() string {
@@ -97,6 +98,8 @@ func (id ID) config() *config.Curve {
 		return &config.BW6_756
 	case SECP256K1:
 		return &config.SECP256K1
+	case P256:
+		return &config.P256
 	default:
 		panic("unimplemented ecc ID")
 	}
@@ -113,4 +116,21 @@ func modulus(c *config.Curve, scalarField bool) *big.Int {
 // MultiExpConfig enables to set optional configuration attribute to a call to MultiExp
 type MultiExpConfig struct {
 	NbTasks int // go routines to be used in the multiexp. can be larger than num cpus.
+
+	// ScalarsMaxBits is an upper bound on the bit length of the scalars, as integers
+	// in [0, r). Small scalars, such as bits or bytes, are processed over fewer windows
+	// and 0/1 scalars only need point additions. If it is ScalarsMaxBitsAuto, the
+	// bound is computed from the scalars; if it is 0 (default), the scalars are
+	// processed over their full width. A scalar larger than the bound gives a wrong result.
+	ScalarsMaxBits int
+
+	// TwistedEdwards runs the multiexp on a twisted Edwards form of the curve, where the
+	// bucket additions are cheaper than in extended Jacobian coordinates. This is faster
+	// for up to a few thousand points; larger multiexps use batch affine additions by
+	// default, which remain faster. It is only supported by BLS12-377 G1 and ignored
+	// elsewhere; the points must then be in the subgroup.
+	TwistedEdwards bool
 }
+
+// ScalarsMaxBitsAuto sets MultiExpConfig.ScalarsMaxBits to be computed from the scalars
+const ScalarsMaxBitsAuto = -1
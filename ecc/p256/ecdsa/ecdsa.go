@@ -0,0 +1,397 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/p256"
+	"github.com/consensys/gnark-crypto/ecc/p256/fp"
+	"github.com/consensys/gnark-crypto/ecc/p256/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+var (
+	errInvalidSig        = errors.New("invalid signature")
+	errInvalidPublicKey  = errors.New("invalid public key")
+	errInvalidPrivateKey = errors.New("invalid private key")
+	errNotOnCurve        = errors.New("point not on curve")
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizeFp         = fp.Bytes
+	sizePublicKey  = 2 * sizeFp
+	sizePrivateKey = sizeFr + sizePublicKey
+	sizeSignature  = 2*sizeFr + 1
+)
+
+var (
+	order     = fr.Modulus()
+	halfOrder = new(big.Int).Rsh(order, 1)
+	one       = big.NewInt(1)
+)
+
+// PublicKey represents an ECDSA public key
+// Q = x⋅G where x is the secret scalar and G the generator
+type PublicKey struct {
+	A p256.G1Affine
+}
+
+// PrivateKey represents an ECDSA private key
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// Signature represents an ECDSA signature (r, s) with its recovery id v.
+//
+// v is the parity of the y coordinate of the point R = k⋅G, with the
+// second bit set in the (negligible probability) case where R.x ≥ r.
+type Signature struct {
+	R, S [sizeFr]byte
+	V    byte
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+
+	k, err := randFieldElement(rand)
+	if err != nil {
+		return nil, err
+
+	}
+	_, g := p256.Generators()
+
+	privateKey := new(PrivateKey)
+	k.FillBytes(privateKey.scalar[:sizeFr])
+	privateKey.PublicKey.A.ScalarMultiplicationCT(&g, k)
+	return privateKey, nil
+}
+
+// HashToInt converts a hash value to an integer. Per FIPS 186-4, ECDSA
+// truncates the hash to the bit length of the curve order.
+func HashToInt(hash []byte) *big.Int {
+	if len(hash) > sizeFr {
+		hash = hash[:sizeFr]
+	}
+	ret := new(big.Int).SetBytes(hash)
+	excess := len(hash)*8 - fr.Bits
+	if excess > 0 {
+		ret.Rsh(ret, uint(excess))
+	}
+	return ret
+}
+
+// randFieldElement returns a random element of the order of the given
+// curve using the procedure given in FIPS 186-4, Appendix B.5.1.
+func randFieldElement(rand io.Reader) (k *big.Int, err error) {
+	b := make([]byte, fr.Bits/8+8)
+	_, err = io.ReadFull(rand, b)
+	if err != nil {
+		return
+	}
+
+	k = new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(order, one)
+	k.Mod(k, n)
+	k.Add(k, one)
+	return
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign performs the ECDSA signature
+//
+// k ← nonce derived from the private key and the message digest (RFC 6979)
+// P = k ⋅ g1Gen
+// r = x_P (mod order)
+// s = k⁻¹ . (m + sk ⋅ r)
+// signature = {r, s, v}
+//
+// s is normalized to the lower half of [1, order-1] and v updated accordingly.
+// If hFunc is not provided, the message is considered to be the digest to sign,
+// else, it is hashed with hFunc.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	digest, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	var x, k, kInv, r, s, yP big.Int
+	x.SetBytes(privKey.scalar[:])
+	e := HashToInt(digest)
+	_, g := p256.Generators()
+
+	var sig Signature
+	var P p256.G1Affine
+	nonces := newNonceGenerator(privKey.scalar[:], digest)
+	for {
+		nonces.next(&k)
+		P.ScalarMultiplicationCT(&g, &k)
+
+		P.X.BigInt(&r)
+		P.Y.BigInt(&yP)
+		sig.V = byte(yP.Bit(0))
+		if r.Cmp(order) >= 0 {
+			sig.V |= 2
+		}
+		r.Mod(&r, order)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		kInv.ModInverse(&k, order)
+		s.Mul(&r, &x).
+			Add(&s, e).
+			Mul(&s, &kInv).
+			Mod(&s, order)
+		if s.Sign() != 0 {
+			break
+		}
+	}
+
+	// low-s normalization
+	if s.Cmp(halfOrder) > 0 {
+		s.Sub(order, &s)
+		sig.V ^= 1
+	}
+
+	r.FillBytes(sig.R[:])
+	s.FillBytes(sig.S[:])
+
+	return sig.Bytes(), nil
+}
+
+// Verify validates the ECDSA signature
+//
+// R ?= (s⁻¹ ⋅ m ⋅ Base + s⁻¹ ⋅ R ⋅ publicKey)_x
+//
+// The recovery id v of the signature is ignored, and s is not required to be low.
+// If hFunc is not provided, the message is considered to be the digest to verify,
+// else, it is hashed with hFunc.
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+
+	// Deserialize the signature
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+
+	if !publicKey.isValid() {
+		return false, errInvalidPublicKey
+	}
+
+	r, s := new(big.Int).SetBytes(sig.R[:]), new(big.Int).SetBytes(sig.S[:])
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return false, nil
+	}
+	if r.Cmp(order) >= 0 || s.Cmp(order) >= 0 {
+		return false, nil
+	}
+
+	digest, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	sInv := new(big.Int).ModInverse(s, order)
+	e := HashToInt(digest)
+	u1 := new(big.Int).Mul(e, sInv)
+	u1.Mod(u1, order)
+	u2 := new(big.Int).Mul(r, sInv)
+	u2.Mod(u2, order)
+
+	_, g := p256.Generators()
+	var U1, U2 p256.G1Jac
+	U1.ScalarMultiplicationAffine(&g, u1)
+	U2.ScalarMultiplicationAffine(&publicKey.A, u2)
+	U1.AddAssign(&U2)
+
+	var P p256.G1Affine
+	P.FromJacobian(&U1)
+	if P.IsInfinity() {
+		return false, nil
+	}
+
+	var xP big.Int
+	P.X.BigInt(&xP)
+	xP.Mod(&xP, order)
+
+	return xP.Cmp(r) == 0, nil
+}
+
+// RecoverPublicKey recovers the public key that signed message, from the signature
+// sigBin = r||s||v.
+//
+// Q = r⁻¹ ⋅ (s ⋅ R - m ⋅ Base) where R is the point of x coordinate r (+ order if v&2)
+// and of y parity v&1.
+// If hFunc is not provided, the message is considered to be the signed digest,
+// else, it is hashed with hFunc.
+func RecoverPublicKey(sigBin, message []byte, hFunc hash.Hash) (*PublicKey, error) {
+
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return nil, err
+	}
+	if sig.V > 3 {
+		return nil, errInvalidSig
+	}
+
+	r, s := new(big.Int).SetBytes(sig.R[:]), new(big.Int).SetBytes(sig.S[:])
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return nil, errInvalidSig
+	}
+	if r.Cmp(order) >= 0 || s.Cmp(order) >= 0 {
+		return nil, errInvalidSig
+	}
+
+	digest, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	// R = (x, y) with x = r (+ order) and y of parity v&1,
+	// which we decode from its SEC1 compressed form
+	x := new(big.Int).Set(r)
+	if sig.V&2 != 0 {
+		x.Add(x, order)
+	}
+	if x.Cmp(fp.Modulus()) >= 0 {
+		return nil, errInvalidSig
+	}
+	var rBin [p256.SizeOfG1AffineCompressed]byte
+	rBin[0] = 0x02 | (sig.V & 1)
+	x.FillBytes(rBin[1:])
+	var R p256.G1Affine
+	if _, err := R.SetBytes(rBin[:]); err != nil {
+		return nil, errNotOnCurve
+	}
+
+	rInv := new(big.Int).ModInverse(r, order)
+	e := HashToInt(digest)
+	u1 := new(big.Int).Mul(e, rInv)
+	u1.Neg(u1).Mod(u1, order)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, order)
+
+	_, g := p256.Generators()
+	var U1, U2 p256.G1Jac
+	U1.ScalarMultiplicationAffine(&g, u1)
+	U2.ScalarMultiplicationAffine(&R, u2)
+	U1.AddAssign(&U2)
+
+	var pub PublicKey
+	pub.A.FromJacobian(&U1)
+	if pub.A.IsInfinity() {
+		return nil, errInvalidSig
+	}
+	return &pub, nil
+}
+
+// isValid returns true if the public key is a point on the curve, distinct from infinity.
+func (publicKey *PublicKey) isValid() bool {
+	return !publicKey.A.IsInfinity() && publicKey.A.IsOnCurve()
+}
+
+// hashMessage returns hFunc(message) if hFunc is not nil, message otherwise.
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// nonceGenerator derives the signing nonces from the secret scalar and
+// the message digest, following RFC 6979 §3.2 with HMAC-SHA256.
+type nonceGenerator struct {
+	k, v []byte
+}
+
+// newNonceGenerator initializes the HMAC_DRBG state from the secret scalar x (int2octets(x))
+// and the message digest.
+func newNonceGenerator(x, digest []byte) *nonceGenerator {
+	// h1 = bits2octets(digest) = int2octets(bits2int(digest) mod order)
+	h1 := make([]byte, sizeFr)
+	new(big.Int).Mod(HashToInt(digest), order).FillBytes(h1)
+
+	g := &nonceGenerator{
+		k: make([]byte, sha256.Size),
+		v: bytes.Repeat([]byte{0x01}, sha256.Size),
+	}
+	g.k = g.mac(g.v, []byte{0x00}, x, h1)
+	g.v = g.mac(g.v)
+	g.k = g.mac(g.v, []byte{0x01}, x, h1)
+	g.v = g.mac(g.v)
+	return g
+}
+
+// mac returns HMAC_K(data[0] || data[1] || ...)
+func (g *nonceGenerator) mac(data ...[]byte) []byte {
+	h := hmac.New(sha256.New, g.k)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// next sets k to the next candidate nonce in [1, order-1].
+func (g *nonceGenerator) next(k *big.Int) {
+	for {
+		t := make([]byte, 0, sizeFr+sha256.Size)
+		for len(t) < sizeFr {
+			g.v = g.mac(g.v)
+			t = append(t, g.v...)
+		}
+		k.Set(HashToInt(t))
+
+		// update the state in case k is rejected (here or by the caller)
+		g.k = g.mac(g.v, []byte{0x00})
+		g.v = g.mac(g.v)
+
+		if k.Sign() > 0 && k.Cmp(order) < 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	crand "crypto/rand"
+
+	"github.com/consensys/gnark-crypto/ecc/p256"
+)
+
+func Example() {
+	// instantiate hash function
+	hFunc := sha256.New()
+
+	// create an ecdsa key pair
+	privateKey, _ := GenerateKey(crand.Reader)
+	publicKey := privateKey.PublicKey
+
+	// sign the message
+	msg := []byte("message")
+	signature, _ := privateKey.Sign(msg, hFunc)
+
+	// verifies signature
+	isValid, _ := publicKey.Verify(signature, msg, hFunc)
+	if !isValid {
+		fmt.Println("1. invalid signature")
+	} else {
+		fmt.Println("1. valid signature")
+	}
+
+	// Output: 1. valid signature
+}
+
+func TestECDSA(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.PublicKey
+	hFunc := sha256.New()
+
+	signature, err := privKey.Sign([]byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifies correct msg
+	res, err := pubKey.Verify(signature, []byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature should return true")
+	}
+
+	// verifies wrong msg
+	res, err = pubKey.Verify(signature, []byte("wrong_message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verify wrong signature should be false")
+	}
+
+	// pre-hashed message
+	digest := sha256.Sum256([]byte("message"))
+	res, err = pubKey.Verify(signature, digest[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature of the digest should return true")
+	}
+
+	// the high-s signature is valid too
+	var sig Signature
+	if _, err := sig.SetBytes(signature); err != nil {
+		t.Fatal(err)
+	}
+	var s big.Int
+	s.SetBytes(sig.S[:])
+	s.Sub(order, &s).FillBytes(sig.S[:])
+	res, err = pubKey.Verify(sig.Bytes(), []byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify high-s signature should return true")
+	}
+}
+
+func TestDeterministicLowS(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hFunc := sha256.New()
+
+	for i := 0; i < 32; i++ {
+		msg := []byte(fmt.Sprintf("message %d", i))
+		sig1, err := privKey.Sign(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig2, err := privKey.Sign(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig1, sig2) {
+			t.Fatal("signatures should be deterministic")
+		}
+
+		var sig Signature
+		if _, err := sig.SetBytes(sig1); err != nil {
+			t.Fatal(err)
+		}
+		if new(big.Int).SetBytes(sig.S[:]).Cmp(halfOrder) > 0 {
+			t.Fatal("s should be in the lower half of the scalar field")
+		}
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+	hFunc := sha256.New()
+
+	for i := 0; i < 16; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte(fmt.Sprintf("message %d", i))
+		signature, err := privKey.Sign(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pub, err := RecoverPublicKey(signature, msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(&privKey.PublicKey) {
+			t.Fatal("recovered public key doesn't match")
+		}
+
+		// flipping the recovery id gives another key
+		signature[sizeSignature-1] ^= 1
+		pub, err = RecoverPublicKey(signature, msg, hFunc)
+		if err == nil && pub.Equal(&privKey.PublicKey) {
+			t.Fatal("recovery with a wrong id should not give the public key")
+		}
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey1, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey1 := privKey1.PublicKey
+
+	privKey2, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey2 := privKey2.PublicKey
+
+	pubKeyBin1 := pubKey1.Bytes()
+	if _, err := pubKey2.SetBytes(pubKeyBin1); err != nil {
+		t.Fatal(err)
+	}
+	pubKeyBin2 := pubKey2.Bytes()
+	if !bytes.Equal(pubKeyBin1, pubKeyBin2) {
+		t.Fatal("Error serialize(deserialize(.))")
+	}
+
+	privKeyBin1 := privKey1.Bytes()
+	if _, err := privKey2.SetBytes(privKeyBin1); err != nil {
+		t.Fatal(err)
+	}
+	privKeyBin2 := privKey2.Bytes()
+	if !bytes.Equal(privKeyBin1, privKeyBin2) {
+		t.Fatal("Error serialize(deserialize(.))")
+	}
+
+	// a point not on the curve is rejected
+	pubKeyBin1[sizePublicKey-1] ^= 1
+	if _, err := pubKey2.SetBytes(pubKeyBin1); err == nil {
+		t.Fatal("point not on the curve should be rejected")
+	}
+}
+
+// TestVector checks deterministic signatures against the test vector
+// of RFC 6979, appendix A.2.5 (P-256, SHA-256, message "sample").
+func TestVector(t *testing.T) {
+	var privKey PrivateKey
+	x, _ := new(big.Int).SetString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721", 16)
+	x.FillBytes(privKey.scalar[:])
+	_, g := p256.Generators()
+	privKey.PublicKey.A.ScalarMultiplication(&g, x)
+
+	signature, err := privKey.Sign([]byte("sample"), sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(signature); err != nil {
+		t.Fatal(err)
+	}
+
+	// the RFC gives a high s, which we normalize
+	var r, s big.Int
+	r.SetString("efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716", 16)
+	s.SetString("f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8", 16)
+	s.Sub(order, &s)
+	if new(big.Int).SetBytes(sig.R[:]).Cmp(&r) != 0 || new(big.Int).SetBytes(sig.S[:]).Cmp(&s) != 0 {
+		t.Fatal("signature doesn't match test vector")
+	}
+}
+
+// benchmarks
+
+func BenchmarkSignECDSA(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	hFunc := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign([]byte("message"), hFunc)
+	}
+}
+
+func BenchmarkVerifyECDSA(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	hFunc := sha256.New()
+	sig, _ := privKey.Sign([]byte("message"), hFunc)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, []byte("message"), hFunc)
+	}
+}
+
+func BenchmarkRecoverPublicKey(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	hFunc := sha256.New()
+	sig, _ := privKey.Sign([]byte("message"), hFunc)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		RecoverPublicKey(sig, []byte("message"), hFunc)
+	}
+}
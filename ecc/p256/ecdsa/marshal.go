@@ -0,0 +1,127 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/subtle"
+	"io"
+	"math/big"
+)
+
+// Bytes returns the binary representation of the public key
+// as x||y where x, y are the coordinates of the point
+// on the curve as big endian integers.
+func (pk *PublicKey) Bytes() []byte {
+	var res [sizePublicKey]byte
+	xBin := pk.A.X.Bytes()
+	yBin := pk.A.Y.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizeFp], xBin[:])
+	subtle.ConstantTimeCopy(1, res[sizeFp:], yBin[:])
+	return res[:]
+}
+
+// SetBytes sets p from binary representation in buf.
+// buf represents a public key as x||y where x, y are
+// interpreted as big endian binary numbers corresponding
+// to the coordinates of a point on the curve.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePublicKey {
+		return n, io.ErrShortBuffer
+	}
+	if err := pk.A.X.SetBytesCanonical(buf[:sizeFp]); err != nil {
+		return 0, err
+	}
+	n += sizeFp
+	if err := pk.A.Y.SetBytesCanonical(buf[sizeFp:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += sizeFp
+	if !pk.isValid() {
+		return n, errNotOnCurve
+	}
+	return n, nil
+}
+
+// Bytes returns the binary representation of pk,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pubkBin[:])
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as  publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePrivateKey {
+		return n, io.ErrShortBuffer
+	}
+	if _, err := privKey.PublicKey.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += sizePublicKey
+	var x big.Int
+	x.SetBytes(buf[sizePublicKey:sizePrivateKey])
+	if x.Sign() == 0 || x.Cmp(order) >= 0 {
+		return n, errInvalidPrivateKey
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[sizePublicKey:sizePrivateKey])
+	n += sizeFr
+	return n, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array of size 2*sizeFr+1 r||s||v where
+//   - r, s are big endian integers of size sizeFr
+//   - v is the recovery id
+func (sig *Signature) Bytes() []byte {
+	var res [sizeSignature]byte
+	subtle.ConstantTimeCopy(1, res[:sizeFr], sig.R[:])
+	subtle.ConstantTimeCopy(1, res[sizeFr:2*sizeFr], sig.S[:])
+	res[2*sizeFr] = sig.V
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary.
+// buf is read interpreted as r||s||v where
+//   - r, s are big endian integers of size sizeFr
+//   - v is the recovery id
+//
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizeSignature {
+		return n, io.ErrShortBuffer
+	}
+	subtle.ConstantTimeCopy(1, sig.R[:], buf[:sizeFr])
+	n += sizeFr
+	subtle.ConstantTimeCopy(1, sig.S[:], buf[sizeFr:2*sizeFr])
+	n += sizeFr
+	sig.V = buf[2*sizeFr]
+	n++
+	return n, nil
+}
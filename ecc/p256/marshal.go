@@ -0,0 +1,723 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package p256
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc/p256/fp"
+	"github.com/consensys/gnark-crypto/ecc/p256/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// To encode G1Affine points, we follow the SEC1 standard (https://www.secg.org/sec1-v2.pdf, section 2.3.3)
+//
+// The first byte of the encoding specifies the format:
+//
+//	0x00 -> point at infinity, encoded as this single byte
+//	0x02 -> compressed point, Y is even (the encoding is followed by X)
+//	0x03 -> compressed point, Y is odd (the encoding is followed by X)
+//	0x04 -> uncompressed point (the encoding is followed by X and Y)
+const (
+	mInfinity       byte = 0x00
+	mCompressedEven byte = 0x02
+	mCompressedOdd  byte = 0x03
+	mUncompressed   byte = 0x04
+)
+
+// SizeOfG1AffineCompressed represents the size in bytes that a G1Affine need in binary form, compressed
+const SizeOfG1AffineCompressed = 1 + fp.Bytes
+
+// SizeOfG1AffineUncompressed represents the size in bytes that a G1Affine need in binary form, uncompressed
+const SizeOfG1AffineUncompressed = 1 + 2*fp.Bytes
+
+// SizeOfG1AffineXY represents the size in bytes of the raw encoding X||Y of a G1Affine (without metadata)
+const SizeOfG1AffineXY = 2 * fp.Bytes
+
+// Encoder writes p256 object values to an output stream
+type Encoder struct {
+	w   io.Writer
+	n   int64 // written bytes
+	raw bool  // raw vs compressed encoding
+}
+
+// Decoder reads p256 object values from an inbound stream
+type Decoder struct {
+	r             io.Reader
+	n             int64 // read bytes
+	subGroupCheck bool  // default to true
+}
+
+// NewDecoder returns a binary decoder supporting curve p256 objects in both
+// compressed and uncompressed (raw) forms
+func NewDecoder(r io.Reader, options ...func(*Decoder)) *Decoder {
+	d := &Decoder{r: r, subGroupCheck: true}
+
+	for _, o := range options {
+		o(d)
+	}
+
+	return d
+}
+
+// Decode reads the binary encoding of v from the stream
+// type must be *uint64, *fr.Element, *fp.Element, *G1Affine, *[]G1Affine, *[]fr.Element or *[]fp.Element
+func (dec *Decoder) Decode(v interface{}) (err error) {
+	rv := reflect.ValueOf(v)
+	if v == nil || rv.Kind() != reflect.Ptr || rv.IsNil() || !rv.Elem().CanSet() {
+		return errors.New("p256 decoder: unsupported type, need pointer")
+	}
+
+	// implementation note: code is a bit verbose (abusing code generation), but minimize allocations on the heap
+	// in particular, careful attention must be given to usage of Bytes() method on Elements and Points
+	// that return an array (not a slice) of bytes. Using this is beneficial to minimize memallocs
+	// in very large (de)serialization upstream in gnark.
+	// (but detrimental to code lisibility here)
+
+	var buf [SizeOfG1AffineUncompressed]byte
+	var read int
+
+	switch t := v.(type) {
+	case *fr.Element:
+		read, err = io.ReadFull(dec.r, buf[:fr.Bytes])
+		dec.n += int64(read)
+		if err != nil {
+			return
+		}
+		err = t.SetBytesCanonical(buf[:fr.Bytes])
+		return
+	case *fp.Element:
+		read, err = io.ReadFull(dec.r, buf[:fp.Bytes])
+		dec.n += int64(read)
+		if err != nil {
+			return
+		}
+		err = t.SetBytesCanonical(buf[:fp.Bytes])
+		return
+	case *[]fr.Element:
+		var sliceLen uint32
+		sliceLen, err = dec.readUint32()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]fr.Element, sliceLen)
+		}
+
+		for i := 0; i < len(*t); i++ {
+			read, err = io.ReadFull(dec.r, buf[:fr.Bytes])
+			dec.n += int64(read)
+			if err != nil {
+				return
+			}
+			if err = (*t)[i].SetBytesCanonical(buf[:fr.Bytes]); err != nil {
+				return
+			}
+		}
+		return
+	case *[]fp.Element:
+		var sliceLen uint32
+		sliceLen, err = dec.readUint32()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]fp.Element, sliceLen)
+		}
+
+		for i := 0; i < len(*t); i++ {
+			read, err = io.ReadFull(dec.r, buf[:fp.Bytes])
+			dec.n += int64(read)
+			if err != nil {
+				return
+			}
+			if err = (*t)[i].SetBytesCanonical(buf[:fp.Bytes]); err != nil {
+				return
+			}
+		}
+		return
+	case *G1Affine:
+		var nbBytes int
+		if nbBytes, err = dec.readG1Affine(buf[:]); err != nil {
+			return
+		}
+		_, err = t.setBytes(buf[:nbBytes], dec.subGroupCheck)
+		return
+	case *[]G1Affine:
+		var sliceLen uint32
+		sliceLen, err = dec.readUint32()
+		if err != nil {
+			return
+		}
+		if len(*t) != int(sliceLen) {
+			*t = make([]G1Affine, sliceLen)
+		}
+		return dec.readG1AffineSlice(*t)
+	default:
+		n := binary.Size(t)
+		if n == -1 {
+			return errors.New("p256 encoder: unsupported type")
+		}
+		err = binary.Read(dec.r, binary.BigEndian, t)
+		if err == nil {
+			dec.n += int64(n)
+		}
+		return
+	}
+}
+
+// readG1AffineSlice decodes len(points) points, the length prefix being already read
+func (dec *Decoder) readG1AffineSlice(points []G1Affine) (err error) {
+	var buf [SizeOfG1AffineUncompressed]byte
+	compressed := make([]bool, len(points))
+	for i := 0; i < len(points); i++ {
+		var nbBytes int
+		if nbBytes, err = dec.readG1Affine(buf[:]); err != nil {
+			return
+		}
+		if buf[0] == mCompressedEven || buf[0] == mCompressedOdd {
+			if err = points[i].unsafeSetCompressedBytes(buf[:nbBytes]); err != nil {
+				return
+			}
+			compressed[i] = true
+		} else if _, err = points[i].setBytes(buf[:nbBytes], false); err != nil {
+			return
+		}
+	}
+	var nbErrs uint64
+	parallel.Execute(len(compressed), func(start, end int) {
+		for i := start; i < end; i++ {
+			if compressed[i] {
+				if err := points[i].unsafeComputeY(dec.subGroupCheck); err != nil {
+					atomic.AddUint64(&nbErrs, 1)
+				}
+			} else if dec.subGroupCheck {
+				if !points[i].IsInSubGroup() {
+					atomic.AddUint64(&nbErrs, 1)
+				}
+			}
+		}
+	})
+	if nbErrs != 0 {
+		return errors.New("point decompression failed")
+	}
+
+	return nil
+}
+
+// BytesRead return total bytes read from reader
+func (dec *Decoder) BytesRead() int64 {
+	return dec.n
+}
+
+func (dec *Decoder) readUint32() (r uint32, err error) {
+	var read int
+	var buf [4]byte
+	read, err = io.ReadFull(dec.r, buf[:4])
+	dec.n += int64(read)
+	if err != nil {
+		return
+	}
+	r = binary.BigEndian.Uint32(buf[:4])
+	return
+}
+
+// readG1Affine reads the SEC1 encoding of a point in buf, starting with the metadata byte
+// which gives the number of bytes left to read. It returns the size of the encoding.
+func (dec *Decoder) readG1Affine(buf []byte) (nbBytes int, err error) {
+	var read int
+	read, err = io.ReadFull(dec.r, buf[:1])
+	dec.n += int64(read)
+	if err != nil {
+		return
+	}
+	switch buf[0] {
+	case mInfinity:
+		return 1, nil
+	case mCompressedEven, mCompressedOdd:
+		nbBytes = SizeOfG1AffineCompressed
+	case mUncompressed:
+		nbBytes = SizeOfG1AffineUncompressed
+	default:
+		return 0, errInvalidEncoding
+	}
+	read, err = io.ReadFull(dec.r, buf[1:nbBytes])
+	dec.n += int64(read)
+	return
+}
+
+// NewEncoder returns a binary encoder supporting curve p256 objects
+func NewEncoder(w io.Writer, options ...func(*Encoder)) *Encoder {
+	// default settings
+	enc := &Encoder{
+		w:   w,
+		n:   0,
+		raw: false,
+	}
+
+	// handle options
+	for _, option := range options {
+		option(enc)
+	}
+
+	return enc
+}
+
+// Encode writes the binary encoding of v to the stream
+// type must be uint64, *fr.Element, *fp.Element, *G1Affine, []G1Affine, []fr.Element or []fp.Element
+func (enc *Encoder) Encode(v interface{}) (err error) {
+	if enc.raw {
+		return enc.encodeRaw(v)
+	}
+	return enc.encode(v)
+}
+
+// BytesWritten return total bytes written on writer
+func (enc *Encoder) BytesWritten() int64 {
+	return enc.n
+}
+
+// RawEncoding returns an option to use in NewEncoder(...) which sets raw encoding mode to true
+// points will not be compressed using this option
+func RawEncoding() func(*Encoder) {
+	return func(enc *Encoder) {
+		enc.raw = true
+	}
+}
+
+// NoSubgroupChecks returns an option to use in NewDecoder(...) which disable subgroup checks on the points
+// the decoder will read. Use with caution, as crafted points from an untrusted source can lead to crypto-attacks.
+func NoSubgroupChecks() func(*Decoder) {
+	return func(dec *Decoder) {
+		dec.subGroupCheck = false
+	}
+}
+
+func (enc *Encoder) encode(v interface{}) (err error) {
+	rv := reflect.ValueOf(v)
+	if v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return errors.New("p256 encoder: can't encode <nil>")
+	}
+
+	// implementation note: code is a bit verbose (abusing code generation), but minimize allocations on the heap
+
+	var written int
+	switch t := v.(type) {
+	case *fr.Element:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case *fp.Element:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case *G1Affine:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:sizeOfEncoding(buf[0])])
+		enc.n += int64(written)
+		return
+	case []fr.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		var buf [fr.Bytes]byte
+		for i := 0; i < len(t); i++ {
+			buf = t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	case []fp.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		var buf [fp.Bytes]byte
+		for i := 0; i < len(t); i++ {
+			buf = t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+
+	case []G1Affine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+
+		var buf [SizeOfG1AffineCompressed]byte
+
+		for i := 0; i < len(t); i++ {
+			buf = t[i].Bytes()
+			written, err = enc.w.Write(buf[:sizeOfEncoding(buf[0])])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	default:
+		n := binary.Size(t)
+		if n == -1 {
+			return errors.New("p256 encoder: unsupported type")
+		}
+		err = binary.Write(enc.w, binary.BigEndian, t)
+		enc.n += int64(n)
+		return
+	}
+}
+
+func (enc *Encoder) encodeRaw(v interface{}) (err error) {
+	rv := reflect.ValueOf(v)
+	if v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return errors.New("p256 encoder: can't encode <nil>")
+	}
+
+	// implementation note: code is a bit verbose (abusing code generation), but minimize allocations on the heap
+
+	var written int
+	switch t := v.(type) {
+	case *fr.Element:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case *fp.Element:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
+	case *G1Affine:
+		buf := t.RawBytes()
+		written, err = enc.w.Write(buf[:sizeOfEncoding(buf[0])])
+		enc.n += int64(written)
+		return
+	case []fr.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		var buf [fr.Bytes]byte
+		for i := 0; i < len(t); i++ {
+			buf = t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	case []fp.Element:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+		var buf [fp.Bytes]byte
+		for i := 0; i < len(t); i++ {
+			buf = t[i].Bytes()
+			written, err = enc.w.Write(buf[:])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+
+	case []G1Affine:
+		// write slice length
+		err = binary.Write(enc.w, binary.BigEndian, uint32(len(t)))
+		if err != nil {
+			return
+		}
+		enc.n += 4
+
+		var buf [SizeOfG1AffineUncompressed]byte
+
+		for i := 0; i < len(t); i++ {
+			buf = t[i].RawBytes()
+			written, err = enc.w.Write(buf[:sizeOfEncoding(buf[0])])
+			enc.n += int64(written)
+			if err != nil {
+				return
+			}
+		}
+		return nil
+	default:
+		n := binary.Size(t)
+		if n == -1 {
+			return errors.New("p256 encoder: unsupported type")
+		}
+		err = binary.Write(enc.w, binary.BigEndian, t)
+		enc.n += int64(n)
+		return
+	}
+}
+
+var errInvalidEncoding = errors.New("invalid point encoding")
+
+// sizeOfEncoding returns the size of a SEC1 encoding from its first byte
+func sizeOfEncoding(mData byte) int {
+	switch mData {
+	case mCompressedEven, mCompressedOdd:
+		return SizeOfG1AffineCompressed
+	case mUncompressed:
+		return SizeOfG1AffineUncompressed
+	default:
+		return 1
+	}
+}
+
+// Marshal converts p to a byte slice (without point compression)
+func (p *G1Affine) Marshal() []byte {
+	b := p.RawBytes()
+	return b[:sizeOfEncoding(b[0])]
+}
+
+// Unmarshal is an allias to SetBytes()
+func (p *G1Affine) Unmarshal(buf []byte) error {
+	_, err := p.SetBytes(buf)
+	return err
+}
+
+// Bytes returns the SEC1 compressed binary representation of p
+// (0x02 or 0x03 depending on the parity of Y, followed by X in big endian).
+//
+// The point at infinity is encoded as the single byte 0x00; the rest of res is then zero.
+func (p *G1Affine) Bytes() (res [SizeOfG1AffineCompressed]byte) {
+
+	// check if p is infinity point
+	if p.X.IsZero() && p.Y.IsZero() {
+		res[0] = mInfinity
+		return
+	}
+
+	res[0] = mCompressedEven
+	if isOdd(&p.Y) {
+		res[0] = mCompressedOdd
+	}
+	xBytes := p.X.Bytes()
+	copy(res[1:], xBytes[:])
+
+	return
+}
+
+// RawBytes returns the SEC1 uncompressed binary representation of p
+// (0x04, followed by X and Y in big endian).
+// see Bytes() for a compressed representation
+//
+// The point at infinity is encoded as the single byte 0x00; the rest of res is then zero.
+func (p *G1Affine) RawBytes() (res [SizeOfG1AffineUncompressed]byte) {
+
+	// check if p is infinity point
+	if p.X.IsZero() && p.Y.IsZero() {
+		res[0] = mInfinity
+		return
+	}
+
+	res[0] = mUncompressed
+	xBytes := p.X.Bytes()
+	yBytes := p.Y.Bytes()
+	copy(res[1:1+fp.Bytes], xBytes[:])
+	copy(res[1+fp.Bytes:], yBytes[:])
+
+	return
+}
+
+// XYBytes returns the raw binary representation X||Y of p, without metadata,
+// where X and Y are in big endian. The point at infinity is encoded as (0,0).
+func (p *G1Affine) XYBytes() (res [SizeOfG1AffineXY]byte) {
+	xBytes := p.X.Bytes()
+	yBytes := p.Y.Bytes()
+	copy(res[:fp.Bytes], xBytes[:])
+	copy(res[fp.Bytes:], yBytes[:])
+	return
+}
+
+// SetXYBytes sets p from the raw binary representation X||Y in buf (see XYBytes) and
+// returns the number of consumed bytes.
+//
+// this check if the resulting point is on the curve
+func (p *G1Affine) SetXYBytes(buf []byte) (int, error) {
+	if len(buf) < SizeOfG1AffineXY {
+		return 0, io.ErrShortBuffer
+	}
+	if err := p.X.SetBytesCanonical(buf[:fp.Bytes]); err != nil {
+		return 0, err
+	}
+	if err := p.Y.SetBytesCanonical(buf[fp.Bytes:SizeOfG1AffineXY]); err != nil {
+		return 0, err
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return 0, errors.New("invalid point: not on the curve")
+	}
+	return SizeOfG1AffineXY, nil
+}
+
+// SetBytes sets p from binary representation in buf and returns number of consumed bytes
+//
+// bytes in buf must match either RawBytes() or Bytes() output, i.e. a SEC1 encoding
+// (compressed, uncompressed or the single byte 0x00 for the point at infinity)
+//
+// if buf is too short io.ErrShortBuffer is returned
+//
+// if buf contains compressed representation (output from Bytes()) and we're unable to compute
+// the Y coordinate (i.e the square root doesn't exist) this function returns an error
+//
+// this check if the resulting point is on the curve and in the correct subgroup
+func (p *G1Affine) SetBytes(buf []byte) (int, error) {
+	return p.setBytes(buf, true)
+}
+
+func (p *G1Affine) setBytes(buf []byte, subGroupCheck bool) (int, error) {
+	if len(buf) < 1 {
+		return 0, io.ErrShortBuffer
+	}
+
+	switch buf[0] {
+	case mInfinity:
+		p.X.SetZero()
+		p.Y.SetZero()
+		return 1, nil
+	case mUncompressed:
+		if len(buf) < SizeOfG1AffineUncompressed {
+			return 0, io.ErrShortBuffer
+		}
+		// read X and Y coordinates
+		if err := p.X.SetBytesCanonical(buf[1 : 1+fp.Bytes]); err != nil {
+			return 0, err
+		}
+		if err := p.Y.SetBytesCanonical(buf[1+fp.Bytes : SizeOfG1AffineUncompressed]); err != nil {
+			return 0, err
+		}
+		// (0,0) isn't on the curve but stands for infinity in affine coordinates: only 0x00 encodes it
+		if p.IsInfinity() {
+			return 0, errInvalidEncoding
+		}
+
+		// subgroup check
+		if subGroupCheck && !p.IsInSubGroup() {
+			return 0, errors.New("invalid point: subgroup check failed")
+		}
+
+		return SizeOfG1AffineUncompressed, nil
+	case mCompressedEven, mCompressedOdd:
+		if len(buf) < SizeOfG1AffineCompressed {
+			return 0, io.ErrShortBuffer
+		}
+	default:
+		return 0, errInvalidEncoding
+	}
+
+	// we have a compressed coordinate, we need to solve the curve equation to compute Y
+	if err := p.X.SetBytesCanonical(buf[1:SizeOfG1AffineCompressed]); err != nil {
+		return 0, err
+	}
+	if err := p.computeY(buf[0]); err != nil {
+		return 0, err
+	}
+
+	// subgroup check
+	if subGroupCheck && !p.IsInSubGroup() {
+		return 0, errors.New("invalid point: subgroup check failed")
+	}
+
+	return SizeOfG1AffineCompressed, nil
+}
+
+// computeY sets p.Y from p.X such that p is on the curve and the parity of p.Y
+// is given by the SEC1 metadata byte mData.
+func (p *G1Affine) computeY(mData byte) error {
+	var YSquared, Y fp.Element
+
+	YSquared.Square(&p.X).Mul(&YSquared, &p.X)
+	Y.Mul(&p.X, &aCurveCoeff)
+	YSquared.Add(&YSquared, &Y)
+	YSquared.Add(&YSquared, &bCurveCoeff)
+
+	if Y.Sqrt(&YSquared) == nil {
+		return errors.New("invalid compressed coordinate: square root doesn't exist")
+	}
+
+	if isOdd(&Y) != (mData == mCompressedOdd) {
+		Y.Neg(&Y)
+	}
+
+	p.Y = Y
+	return nil
+}
+
+// unsafeComputeY called by Decoder when processing slices of compressed point in parallel (step 2)
+// it computes the Y coordinate from the already set X coordinate and is compute intensive
+func (p *G1Affine) unsafeComputeY(subGroupCheck bool) error {
+	// stored in unsafeSetCompressedBytes
+	mData := byte(p.Y[0])
+
+	if err := p.computeY(mData); err != nil {
+		return err
+	}
+
+	// subgroup check
+	if subGroupCheck && !p.IsInSubGroup() {
+		return errors.New("invalid point: subgroup check failed")
+	}
+
+	return nil
+}
+
+// unsafeSetCompressedBytes is called by Decoder when processing slices of compressed point in parallel (step 1)
+// assumes buf[0] is mCompressedEven or mCompressedOdd
+// it sets X coordinate and uses Y for scratch space to store decompression metadata
+func (p *G1Affine) unsafeSetCompressedBytes(buf []byte) error {
+	if err := p.X.SetBytesCanonical(buf[1:SizeOfG1AffineCompressed]); err != nil {
+		return err
+	}
+	// store mData in p.Y[0]
+	p.Y[0] = uint64(buf[0])
+
+	// recomputing Y will be done asynchronously
+	return nil
+}
+
+// isOdd returns true if the regular (non-Montgomery) form of y is odd
+func isOdd(y *fp.Element) bool {
+	yBytes := y.Bytes()
+	return yBytes[fp.Bytes-1]&1 == 1
+}
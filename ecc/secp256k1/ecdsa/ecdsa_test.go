@@ -0,0 +1,290 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	crand "crypto/rand"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+)
+
+func Example() {
+	// instantiate hash function
+	hFunc := sha256.New()
+
+	// create an ecdsa key pair
+	privateKey, _ := GenerateKey(crand.Reader)
+	publicKey := privateKey.PublicKey
+
+	// sign the message
+	msg := []byte("message")
+	signature, _ := privateKey.Sign(msg, hFunc)
+
+	// verifies signature
+	isValid, _ := publicKey.Verify(signature, msg, hFunc)
+	if !isValid {
+		fmt.Println("1. invalid signature")
+	} else {
+		fmt.Println("1. valid signature")
+	}
+
+	// Output: 1. valid signature
+}
+
+func TestECDSA(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.PublicKey
+	hFunc := sha256.New()
+
+	signature, err := privKey.Sign([]byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifies correct msg
+	res, err := pubKey.Verify(signature, []byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature should return true")
+	}
+
+	// verifies wrong msg
+	res, err = pubKey.Verify(signature, []byte("wrong_message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verify wrong signature should be false")
+	}
+
+	// pre-hashed message
+	digest := sha256.Sum256([]byte("message"))
+	res, err = pubKey.Verify(signature, digest[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature of the digest should return true")
+	}
+
+	// the high-s signature is valid too
+	var sig Signature
+	if _, err := sig.SetBytes(signature); err != nil {
+		t.Fatal(err)
+	}
+	var s big.Int
+	s.SetBytes(sig.S[:])
+	s.Sub(order, &s).FillBytes(sig.S[:])
+	res, err = pubKey.Verify(sig.Bytes(), []byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify high-s signature should return true")
+	}
+}
+
+func TestDeterministicLowS(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hFunc := sha256.New()
+
+	for i := 0; i < 32; i++ {
+		msg := []byte(fmt.Sprintf("message %d", i))
+		sig1, err := privKey.Sign(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig2, err := privKey.Sign(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig1, sig2) {
+			t.Fatal("signatures should be deterministic")
+		}
+
+		var sig Signature
+		if _, err := sig.SetBytes(sig1); err != nil {
+			t.Fatal(err)
+		}
+		if new(big.Int).SetBytes(sig.S[:]).Cmp(halfOrder) > 0 {
+			t.Fatal("s should be in the lower half of the scalar field")
+		}
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+	hFunc := sha256.New()
+
+	for i := 0; i < 16; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte(fmt.Sprintf("message %d", i))
+		signature, err := privKey.Sign(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pub, err := RecoverPublicKey(signature, msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(&privKey.PublicKey) {
+			t.Fatal("recovered public key doesn't match")
+		}
+
+		// flipping the recovery id gives another key
+		signature[sizeSignature-1] ^= 1
+		pub, err = RecoverPublicKey(signature, msg, hFunc)
+		if err == nil && pub.Equal(&privKey.PublicKey) {
+			t.Fatal("recovery with a wrong id should not give the public key")
+		}
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey1, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey1 := privKey1.PublicKey
+
+	privKey2, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey2 := privKey2.PublicKey
+
+	pubKeyBin1 := pubKey1.Bytes()
+	if _, err := pubKey2.SetBytes(pubKeyBin1); err != nil {
+		t.Fatal(err)
+	}
+	pubKeyBin2 := pubKey2.Bytes()
+	if !bytes.Equal(pubKeyBin1, pubKeyBin2) {
+		t.Fatal("Error serialize(deserialize(.))")
+	}
+
+	privKeyBin1 := privKey1.Bytes()
+	if _, err := privKey2.SetBytes(privKeyBin1); err != nil {
+		t.Fatal(err)
+	}
+	privKeyBin2 := privKey2.Bytes()
+	if !bytes.Equal(privKeyBin1, privKeyBin2) {
+		t.Fatal("Error serialize(deserialize(.))")
+	}
+
+	// a point not on the curve is rejected
+	pubKeyBin1[sizePublicKey-1] ^= 1
+	if _, err := pubKey2.SetBytes(pubKeyBin1); err == nil {
+		t.Fatal("point not on the curve should be rejected")
+	}
+}
+
+// TestVector checks deterministic signatures against test vectors
+// from the bitcoin ecosystem (private key 1, SHA256 digest).
+func TestVector(t *testing.T) {
+	var privKey PrivateKey
+	privKey.scalar[sizeFr-1] = 1
+	_, privKey.PublicKey.A = secp256k1.Generators()
+
+	sig, err := privKey.Sign([]byte("Satoshi Nakamoto"), sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8" +
+		"2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	if fmt.Sprintf("%x", sig[:2*sizeFr]) != expected {
+		t.Fatal("signature doesn't match test vector")
+	}
+}
+
+// benchmarks
+
+func BenchmarkSignECDSA(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	hFunc := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign([]byte("message"), hFunc)
+	}
+}
+
+func BenchmarkVerifyECDSA(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	hFunc := sha256.New()
+	sig, _ := privKey.Sign([]byte("message"), hFunc)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, []byte("message"), hFunc)
+	}
+}
+
+func BenchmarkRecoverPublicKey(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	hFunc := sha256.New()
+	sig, _ := privKey.Sign([]byte("message"), hFunc)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		RecoverPublicKey(sig, []byte("message"), hFunc)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schnorr provides BIP-340 Schnorr signatures on secp256k1.
+//
+// Public keys are x-only (32 bytes): the secret key is negated when needed
+// so that the public point has an even y coordinate. Signatures are 64 bytes.
+// The package also provides batch verification and the BIP-341 (Taproot) key
+// tweaking.
+//
+// # See also
+//
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+// https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki
+package schnorr
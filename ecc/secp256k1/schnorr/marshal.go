@@ -0,0 +1,109 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/subtle"
+	"io"
+	"math/big"
+)
+
+// Bytes returns the binary representation of the public key:
+// the x coordinate of the point as a big endian integer (x-only encoding).
+func (pk *PublicKey) Bytes() []byte {
+	var res [sizePublicKey]byte
+	pkBin := pk.A.X.Bytes()
+	subtle.ConstantTimeCopy(1, res[:], pkBin[:])
+	return res[:]
+}
+
+// SetBytes sets pk from its x-only binary representation in buf:
+// the point of x coordinate x (big endian) with an even y coordinate.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	if err := liftX(&pk.A, buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	return sizePublicKey, nil
+}
+
+// Bytes returns the binary representation of privKey,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pubkBin)
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets privKey from buf, where buf is interpreted
+// as publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePrivateKey {
+		return n, io.ErrShortBuffer
+	}
+	if _, err := privKey.PublicKey.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += sizePublicKey
+	var d big.Int
+	d.SetBytes(buf[sizePublicKey:sizePrivateKey])
+	if d.Sign() == 0 || d.Cmp(order) >= 0 {
+		return n, errInvalidPrivateKey
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[sizePublicKey:sizePrivateKey])
+	n += sizeFr
+	return n, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array of size 64 r||s where
+//   - r is the x coordinate of the nonce point R, in big endian
+//   - s is in big endian, of size sizeFr
+func (sig *Signature) Bytes() []byte {
+	var res [sizeSignature]byte
+	subtle.ConstantTimeCopy(1, res[:sizeFp], sig.R[:])
+	subtle.ConstantTimeCopy(1, res[sizeFp:], sig.S[:])
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary.
+// buf is read interpreted as r||s where
+//   - r is the x coordinate of the nonce point R, in big endian
+//   - s is in big endian, of size sizeFr
+//
+// Range checks on r and s are performed by Verify.
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizeSignature {
+		return n, io.ErrShortBuffer
+	}
+	subtle.ConstantTimeCopy(1, sig.R[:], buf[:sizeFp])
+	n += sizeFp
+	subtle.ConstantTimeCopy(1, sig.S[:], buf[sizeFp:sizeSignature])
+	n += sizeFr
+	return n, nil
+}
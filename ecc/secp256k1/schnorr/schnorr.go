@@ -0,0 +1,381 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+var (
+	errNotOnCurve        = errors.New("x coordinate is not on the curve")
+	errInvalidPrivateKey = errors.New("invalid private key")
+	errInvalidTweak      = errors.New("tweak is not smaller than the group order")
+	errInconsistentInput = errors.New("number of public keys, messages and signatures differ")
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizeFp         = fp.Bytes
+	sizePublicKey  = sizeFp
+	sizePrivateKey = sizePublicKey + sizeFr
+	sizeSignature  = sizeFp + sizeFr
+)
+
+var order = fr.Modulus()
+
+// tags of the BIP-340 tagged hashes
+const (
+	tagAux       = "BIP0340/aux"
+	tagNonce     = "BIP0340/nonce"
+	tagChallenge = "BIP0340/challenge"
+)
+
+// PublicKey x-only public key: the point P = d⋅G with an even y coordinate.
+type PublicKey struct {
+	A secp256k1.G1Affine
+}
+
+// PrivateKey private key of a BIP-340 instance
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar d', in big Endian (d'⋅G may have an odd y coordinate)
+}
+
+// Signature represents a BIP-340 signature (r, s)
+// where r is the x coordinate of R = k⋅G (with even y).
+type Signature struct {
+	R [sizeFp]byte
+	S [sizeFr]byte
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(r io.Reader) (*PrivateKey, error) {
+	b := make([]byte, fr.Bits/8+8)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	// d' ∈ [1, order-1], following FIPS 186-4, Appendix B.5.1
+	k := new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(order, big.NewInt(1))
+	k.Mod(k, n).Add(k, big.NewInt(1))
+
+	var priv PrivateKey
+	k.FillBytes(priv.scalar[:])
+	priv.setPublicKey()
+	return &priv, nil
+}
+
+// setPublicKey computes the x-only public key from the secret scalar.
+func (privKey *PrivateKey) setPublicKey() {
+	var d big.Int
+	d.SetBytes(privKey.scalar[:])
+	_, g := secp256k1.Generators()
+	privKey.PublicKey.A.ScalarMultiplicationCT(&g, &d)
+	if !hasEvenY(&privKey.PublicKey.A) {
+		privKey.PublicKey.A.Neg(&privKey.PublicKey.A)
+	}
+}
+
+// TaggedHash returns SHA256(SHA256(tag) || SHA256(tag) || msg[0] || msg[1] || ...)
+func TaggedHash(tag string, msg ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msg {
+		h.Write(m)
+	}
+	var res [32]byte
+	h.Sum(res[:0])
+	return res
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign signs a message with fresh auxiliary randomness from crypto/rand,
+// as recommended by BIP-340.
+// If hFunc is not provided, the message is signed as is (BIP-340 messages
+// have arbitrary length), else, it is first hashed with hFunc.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	var aux [32]byte
+	if _, err := io.ReadFull(rand.Reader, aux[:]); err != nil {
+		return nil, err
+	}
+	return privKey.SignWithAux(message, aux[:], hFunc)
+}
+
+// SignWithAux signs a message following BIP-340, with the 32 bytes of
+// auxiliary randomness aux (all zeros is acceptable but leaks more
+// in case of side channels).
+//
+// d = d' if P = d'⋅G has an even y, order-d' otherwise
+// t = d ⊕ hash_aux(aux)
+// k' = hash_nonce(t || P || m), R = k'⋅G, k = ±k' so that R has an even y
+// e = hash_challenge(R || P || m)
+// signature = R || k + e⋅d
+func (privKey *PrivateKey) SignWithAux(message, aux []byte, hFunc hash.Hash) ([]byte, error) {
+	msg, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := privKey.secretScalar()
+	if err != nil {
+		return nil, err
+	}
+	var dBin [sizeFr]byte
+	d.FillBytes(dBin[:])
+	pBin := privKey.PublicKey.Bytes()
+
+	t := TaggedHash(tagAux, aux)
+	for i := range t {
+		t[i] ^= dBin[i]
+	}
+	nonce := TaggedHash(tagNonce, t[:], pBin, msg)
+	var k big.Int
+	k.SetBytes(nonce[:]).Mod(&k, order)
+	if k.Sign() == 0 {
+		return nil, errors.New("nonce is zero")
+	}
+
+	var R secp256k1.G1Affine
+	_, g := secp256k1.Generators()
+	R.ScalarMultiplicationCT(&g, &k)
+	if !hasEvenY(&R) {
+		k.Sub(order, &k)
+	}
+
+	var sig Signature
+	sig.R = R.X.Bytes()
+	e := challenge(sig.R[:], pBin, msg)
+
+	var s big.Int
+	s.Mul(e, d).Add(&s, &k).Mod(&s, order)
+	s.FillBytes(sig.S[:])
+
+	// verify the signature, as a protection against fault attacks
+	if ok, err := privKey.PublicKey.verify(&sig, msg); err != nil || !ok {
+		return nil, errors.New("signature verification failed")
+	}
+
+	return sig.Bytes(), nil
+}
+
+// secretScalar returns the secret scalar d, negated if d⋅G has an odd y.
+func (privKey *PrivateKey) secretScalar() (*big.Int, error) {
+	d := new(big.Int).SetBytes(privKey.scalar[:])
+	if d.Sign() == 0 || d.Cmp(order) >= 0 {
+		return nil, errInvalidPrivateKey
+	}
+	var P secp256k1.G1Affine
+	_, g := secp256k1.Generators()
+	P.ScalarMultiplicationCT(&g, d)
+	if !hasEvenY(&P) {
+		d.Sub(order, d)
+	}
+	return d, nil
+}
+
+// Verify verifies a BIP-340 signature
+//
+// R = s⋅G - hash_challenge(r || P || m)⋅P
+// and checks that R is not infinity, has an even y and x(R) = r.
+// If hFunc is not provided, the message is verified as is, else, it is first
+// hashed with hFunc.
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+
+	// Deserialize the signature
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+
+	msg, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	return pub.verify(&sig, msg)
+}
+
+func (pub *PublicKey) verify(sig *Signature, msg []byte) (bool, error) {
+	if !pub.A.IsOnCurve() || pub.A.IsInfinity() || !hasEvenY(&pub.A) {
+		return false, errNotOnCurve
+	}
+
+	r, s := new(big.Int).SetBytes(sig.R[:]), new(big.Int).SetBytes(sig.S[:])
+	if r.Cmp(fp.Modulus()) >= 0 || s.Cmp(order) >= 0 {
+		return false, nil
+	}
+	pBin := pub.Bytes()
+	e := challenge(sig.R[:], pBin, msg)
+
+	// R = s⋅G - e⋅P
+	_, g := secp256k1.Generators()
+	var sG, eP secp256k1.G1Jac
+	sG.ScalarMultiplicationAffine(&g, s)
+	eP.ScalarMultiplicationAffine(&pub.A, e)
+	sG.SubAssign(&eP)
+
+	var R secp256k1.G1Affine
+	R.FromJacobian(&sG)
+	if R.IsInfinity() || !hasEvenY(&R) {
+		return false, nil
+	}
+	rBin := R.X.Bytes()
+	return subtle.ConstantTimeCompare(rBin[:], sig.R[:]) == 1, nil
+}
+
+// BatchVerify verifies signatures[i] of messages[i] by publicKeys[i] at once,
+// following the BIP-340 batch verification algorithm:
+//
+// (s₁ + a₂s₂ + … + aₙsₙ)⋅G = R₁ + a₂⋅R₂ + … + aₙ⋅Rₙ + e₁⋅P₁ + (a₂e₂)⋅P₂ + … + (aₙeₙ)⋅Pₙ
+//
+// where the aᵢ are random scalars. It returns true only if all the signatures are valid.
+// If hFunc is not provided, the messages are verified as is, else, they are first
+// hashed with hFunc.
+func BatchVerify(publicKeys []PublicKey, messages, signatures [][]byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) != len(messages) || len(publicKeys) != len(signatures) {
+		return false, errInconsistentInput
+	}
+	if len(publicKeys) == 0 {
+		return true, nil
+	}
+
+	// we check that ∑aᵢ⋅Rᵢ + (aᵢeᵢ)⋅Pᵢ - (∑aᵢ⋅sᵢ)⋅G is the point at infinity
+	// with a single multi-scalar multiplication
+	points := make([]secp256k1.G1Affine, 2*len(publicKeys)+1)
+	scalars := make([]fr.Element, 2*len(publicKeys)+1)
+
+	var lhs big.Int
+	var sig Signature
+	var a, s, ae big.Int
+	a.SetUint64(1)
+	aBin := make([]byte, fr.Bits/8+8)
+	for i := range publicKeys {
+		if !publicKeys[i].A.IsOnCurve() || publicKeys[i].A.IsInfinity() || !hasEvenY(&publicKeys[i].A) {
+			return false, errNotOnCurve
+		}
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return false, err
+		}
+		msg, err := hashMessage(messages[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+
+		s.SetBytes(sig.S[:])
+		if s.Cmp(order) >= 0 {
+			return false, nil
+		}
+		if err := liftX(&points[2*i], sig.R[:]); err != nil {
+			return false, nil
+		}
+		e := challenge(sig.R[:], publicKeys[i].Bytes(), msg)
+
+		if i > 0 {
+			// aᵢ ∈ [1, order-1]
+			if _, err := io.ReadFull(rand.Reader, aBin); err != nil {
+				return false, err
+			}
+			a.SetBytes(aBin).Mod(&a, new(big.Int).Sub(order, big.NewInt(1))).Add(&a, big.NewInt(1))
+		}
+
+		// lhs += aᵢ⋅sᵢ
+		s.Mul(&s, &a)
+		lhs.Add(&lhs, &s)
+
+		// aᵢ⋅Rᵢ + (aᵢeᵢ)⋅Pᵢ
+		ae.Mul(&a, e)
+		scalars[2*i].SetBigInt(&a)
+		scalars[2*i+1].SetBigInt(&ae)
+		points[2*i+1].Set(&publicKeys[i].A)
+	}
+
+	_, points[len(points)-1] = secp256k1.Generators()
+	scalars[len(scalars)-1].SetBigInt(&lhs).Neg(&scalars[len(scalars)-1])
+
+	var res secp256k1.G1Affine
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+
+	return res.IsInfinity(), nil
+}
+
+// challenge returns int(hash_challenge(r || p || m)) mod order
+func challenge(r, p, m []byte) *big.Int {
+	h := TaggedHash(tagChallenge, r, p, m)
+	e := new(big.Int).SetBytes(h[:])
+	return e.Mod(e, order)
+}
+
+// hasEvenY returns true if the y coordinate of p is even
+func hasEvenY(p *secp256k1.G1Affine) bool {
+	return p.Y.Bits()[0]&1 == 0
+}
+
+// liftX sets p to the point of x coordinate x (big endian) with an even y coordinate.
+func liftX(p *secp256k1.G1Affine, x []byte) error {
+	// this is the SEC1 decompression of 0x02||x
+	var buf [secp256k1.SizeOfG1AffineCompressed]byte
+	buf[0] = 0x02
+	copy(buf[1:], x)
+	if _, err := p.SetBytes(buf[:]); err != nil {
+		return errNotOnCurve
+	}
+	return nil
+}
+
+// hashMessage returns hFunc(message) if hFunc is not nil, message otherwise.
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
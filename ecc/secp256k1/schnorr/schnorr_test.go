@@ -0,0 +1,309 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	crand "crypto/rand"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+)
+
+// bip340Vectors are the official BIP-340 test vectors
+// (bip-0340/test-vectors.csv)
+const bip340Vectors = `index,secret key,public key,aux_rand,message,signature,verification result,comment
+0,0000000000000000000000000000000000000000000000000000000000000003,F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9,0000000000000000000000000000000000000000000000000000000000000000,0000000000000000000000000000000000000000000000000000000000000000,E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0,TRUE,
+1,B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,0000000000000000000000000000000000000000000000000000000000000001,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A,TRUE,
+2,C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9,DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8,C87AA53824B4D7AE2EB035A2B5BBBCCC080E76CDC6D1692C4B0B62D798E6D906,7E2D58D8B3BCDF1ABADEC7829054F90DDA9805AAB56C77333024B9D0A508B75C,5831AAEED7B44BB74E5EAB94BA9D4294C49BCF2A60728D8B4C200F50DD313C1BAB745879A5AD954A72C45A91C3A51D3C7ADEA98D82F8481E0E1E03674A6F3FB7,TRUE,
+3,0B432B2677937381AEF05BB02A66ECD012773062CF3FA2549E44F58ED2401710,25D1DFF95105F5253C4022F628A996AD3A0D95FBF21D468A1B33F8C160D8F517,FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF,FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF,7EB0509757E246F19449885651611CB965ECC1A187DD51B64FDA1EDC9637D5EC97582B9CB13DB3933705B32BA982AF5AF25FD78881EBB32771FC5922EFC66EA3,TRUE,test fails if msg is reduced modulo p or n
+4,,D69C3509BB99E412E68B0FE8544E72837DFA30746D8BE2AA65975F29D22DC7B9,,4DF3C3F68FCC83B27E9D42C90431A72499F17875C81A599B566C9889B9696703,00000000000000000000003B78CE563F89A0ED9414F5AA28AD0D96D6795F9C6376AFB1548AF603B3EB45C9F8207DEE1060CB71C04E80F593060B07D28308D7F4,TRUE,
+5,,EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B,FALSE,public key not on the curve
+6,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,FFF97BD5755EEEA420453A14355235D382F6472F8568A18B2F057A14602975563CC27944640AC607CD107AE10923D9EF7A73C643E166BE5EBEAFA34B1AC553E2,FALSE,has_even_y(R) is false
+7,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,1FA62E331EDBC21C394792D2AB1100A7B432B013DF3F6FF4F99FCB33E0E1515F28890B3EDB6E7189B630448B515CE4F8622A954CFE545735AAEA5134FCCDB2BD,FALSE,negated message
+8,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769961764B3AA9B2FFCB6EF947B6887A226E8D7C93E00C5ED0C1834FF0D0C2E6DA6,FALSE,negated s value
+9,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,0000000000000000000000000000000000000000000000000000000000000000123DDA8328AF9C23A94C1FEECFD123BA4FB73476F0D594DCB65C6425BD186051,FALSE,sG - eP is infinite. Test fails in single verification if has_even_y(inf) is defined as true and x(inf) as 0
+10,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,00000000000000000000000000000000000000000000000000000000000000017615FBAF5AE28864013C099742DEADB4DBA87F11AC6754F93780D5A1837CF197,FALSE,sG - eP is infinite. Test fails in single verification if has_even_y(inf) is defined as true and x(inf) as 1
+11,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,4A298DACAE57395A15D0795DDBFD1DCB564DA82B0F269BC70A74F8220429BA1D69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B,FALSE,sig[0:32] is not an X coordinate on the curve
+12,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B,FALSE,sig[0:32] is equal to field size
+13,,DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141,FALSE,sig[32:64] is equal to curve order
+14,,FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30,,243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89,6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B,FALSE,public key is not a valid X coordinate because it exceeds the field size
+15,0340034003400340034003400340034003400340034003400340034003400340,778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117,0000000000000000000000000000000000000000000000000000000000000000,,71535DB165ECD9FBBC046E5FFAEA61186BB6AD436732FCCC25291A55895464CF6069CE26BF03466228F19A3A62DB8A649F2D560FAC652827D1AF0574E427AB63,TRUE,message of size 0 (added 2022-12)
+16,0340034003400340034003400340034003400340034003400340034003400340,778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117,0000000000000000000000000000000000000000000000000000000000000000,11,08A20A0AFEF64124649232E0693C583AB1B9934AE63B4C3511F3AE1134C6A303EA3173BFEA6683BD101FA5AA5DBC1996FE7CACFC5A577D33EC14564CEC2BACBF,TRUE,message of size 1 (added 2022-12)
+17,0340034003400340034003400340034003400340034003400340034003400340,778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117,0000000000000000000000000000000000000000000000000000000000000000,0102030405060708090A0B0C0D0E0F1011,5130F39A4059B43BC7CAC09A19ECE52B5D8699D1A71E3C52DA9AFDB6B50AC370C4A482B77BF960F8681540E25B6771ECE1E5A37FD80E5A51897C5566A97EA5A5,TRUE,message of size 17 (added 2022-12)
+18,0340034003400340034003400340034003400340034003400340034003400340,778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117,0000000000000000000000000000000000000000000000000000000000000000,` + "99999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999" + `,403B12B0D8555A344175EA7EC746566303321E5DBFA8BE6F091635163ECA79A8585ED3E3170807E7C03B720FC54C7B23897FCBA0E9D0B4A06894CFD249F22367,TRUE,message of size 100 (added 2022-12)
+`
+
+func Example() {
+	// create a schnorr key pair
+	privateKey, _ := GenerateKey(crand.Reader)
+	publicKey := privateKey.PublicKey
+
+	// sign the message
+	msg := sha256.Sum256([]byte("message"))
+	signature, _ := privateKey.Sign(msg[:], nil)
+
+	// verifies signature
+	isValid, _ := publicKey.Verify(signature, msg[:], nil)
+	if !isValid {
+		fmt.Println("1. invalid signature")
+	} else {
+		fmt.Println("1. valid signature")
+	}
+
+	// Output: 1. valid signature
+}
+
+func TestBIP340Vectors(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(bip340Vectors)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range records[1:] {
+		index := record[0]
+		sk, _ := hex.DecodeString(record[1])
+		pk, _ := hex.DecodeString(record[2])
+		aux, _ := hex.DecodeString(record[3])
+		msg, _ := hex.DecodeString(record[4])
+		sig, _ := hex.DecodeString(record[5])
+		expected := record[6] == "TRUE"
+
+		if len(sk) != 0 {
+			var privKey PrivateKey
+			copy(privKey.scalar[:], sk)
+			privKey.setPublicKey()
+			if !bytes.Equal(privKey.PublicKey.Bytes(), pk) {
+				t.Fatalf("vector %s: wrong public key", index)
+			}
+			res, err := privKey.SignWithAux(msg, aux, nil)
+			if err != nil {
+				t.Fatalf("vector %s: %v", index, err)
+			}
+			if !bytes.Equal(res, sig) {
+				t.Fatalf("vector %s: wrong signature", index)
+			}
+		}
+
+		var pubKey PublicKey
+		if _, err := pubKey.SetBytes(pk); err != nil {
+			if expected {
+				t.Fatalf("vector %s: %v", index, err)
+			}
+			continue
+		}
+		res, _ := pubKey.Verify(sig, msg, nil)
+		if res != expected {
+			t.Fatalf("vector %s: expected %t, got %t", index, expected, res)
+		}
+		res, _ = BatchVerify([]PublicKey{pubKey}, [][]byte{msg}, [][]byte{sig}, nil)
+		if res != expected {
+			t.Fatalf("vector %s: batch: expected %t, got %t", index, expected, res)
+		}
+	}
+}
+
+func TestSchnorr(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.PublicKey
+	hFunc := sha256.New()
+
+	signature, err := privKey.Sign([]byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifies correct msg
+	res, err := pubKey.Verify(signature, []byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature should return true")
+	}
+
+	// verifies wrong msg
+	res, err = pubKey.Verify(signature, []byte("wrong_message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verify wrong signature should be false")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	const n = 8
+	pks := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks[i] = privKey.PublicKey
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sigs[i], err = privKey.Sign(msgs[i], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := BatchVerify(pks, msgs, sigs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("batch verification of valid signatures should return true")
+	}
+
+	msgs[3] = []byte("wrong_message")
+	res, err = BatchVerify(pks, msgs, sigs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("batch verification with a wrong message should return false")
+	}
+}
+
+func TestTweak(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	for i := 0; i < 8; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		merkleRoot := make([]byte, 32*(i%2))
+		r.Read(merkleRoot)
+
+		outputKey, parity, err := privKey.PublicKey.Tweak(merkleRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tweakedPrivKey, err := privKey.Tweak(merkleRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !tweakedPrivKey.PublicKey.Equal(outputKey) {
+			t.Fatal("tweaked private key doesn't match the output key")
+		}
+
+		// Q = P + t⋅G has the parity returned by Tweak
+		t0, _ := tapTweak(&privKey.PublicKey, merkleRoot)
+		d, _ := privKey.secretScalar()
+		d.Add(d, t0).Mod(d, order)
+		var Q PublicKey
+		_, g := secp256k1.Generators()
+		Q.A.ScalarMultiplication(&g, d)
+		if hasEvenY(&Q.A) != (parity == 0) {
+			t.Fatal("wrong output key parity")
+		}
+
+		signature, err := tweakedPrivKey.Sign([]byte("message"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := outputKey.Verify(signature, []byte("message"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("signature with the tweaked key should verify under the output key")
+		}
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey1, err := GenerateKey(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey1 := privKey1.PublicKey
+
+	var pubKey2 PublicKey
+	if _, err := pubKey2.SetBytes(pubKey1.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !pubKey1.Equal(&pubKey2) || !pubKey1.A.Equal(&pubKey2.A) {
+		t.Fatal("Error serialize(deserialize(.))")
+	}
+
+	var privKey2 PrivateKey
+	if _, err := privKey2.SetBytes(privKey1.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(privKey1.Bytes(), privKey2.Bytes()) {
+		t.Fatal("Error serialize(deserialize(.))")
+	}
+}
+
+// benchmarks
+
+func BenchmarkSign(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	msg := sha256.Sum256([]byte("message"))
+	var aux [32]byte
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.SignWithAux(msg[:], aux[:], nil)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 -- test only
+
+	privKey, _ := GenerateKey(r)
+	msg := sha256.Sum256([]byte("message"))
+	sig, _ := privKey.Sign(msg[:], nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg[:], nil)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+)
+
+// tag of the BIP-341 tweak hash
+const tagTapTweak = "TapTweak"
+
+// Tweak returns the BIP-341 taproot output key Q = P + t⋅G where
+// t = hash_TapTweak(P || merkleRoot), together with the parity of the y
+// coordinate of Q (needed in the control block of script path spends).
+// merkleRoot is empty for outputs without a script tree.
+func (pub *PublicKey) Tweak(merkleRoot []byte) (*PublicKey, uint, error) {
+	t, err := tapTweak(pub, merkleRoot)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var Q secp256k1.G1Jac
+	_, g := secp256k1.Generators()
+	Q.ScalarMultiplicationAffine(&g, t)
+	Q.AddMixed(&pub.A)
+
+	var res PublicKey
+	res.A.FromJacobian(&Q)
+	if res.A.IsInfinity() {
+		return nil, 0, errInvalidTweak
+	}
+	var parity uint
+	if !hasEvenY(&res.A) {
+		parity = 1
+		res.A.Neg(&res.A)
+	}
+	return &res, parity, nil
+}
+
+// Tweak returns the private key d + t (with d negated if d⋅G has an odd y)
+// where t = hash_TapTweak(P || merkleRoot), that signs for the output key
+// returned by PublicKey.Tweak (BIP-341 key path spending).
+func (privKey *PrivateKey) Tweak(merkleRoot []byte) (*PrivateKey, error) {
+	d, err := privKey.secretScalar()
+	if err != nil {
+		return nil, err
+	}
+	t, err := tapTweak(&privKey.PublicKey, merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	d.Add(d, t).Mod(d, order)
+	if d.Sign() == 0 {
+		return nil, errInvalidTweak
+	}
+
+	var res PrivateKey
+	d.FillBytes(res.scalar[:])
+	res.setPublicKey()
+	return &res, nil
+}
+
+// tapTweak returns t = int(hash_TapTweak(P || merkleRoot)), failing if t ≥ order.
+func tapTweak(pub *PublicKey, merkleRoot []byte) (*big.Int, error) {
+	h := TaggedHash(tagTapTweak, pub.Bytes(), merkleRoot)
+	t := new(big.Int).SetBytes(h[:])
+	if t.Cmp(order) >= 0 {
+		return nil, errInvalidTweak
+	}
+	return t, nil
+}
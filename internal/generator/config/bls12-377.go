@@ -11,6 +11,7 @@ var BLS12_377 = Curve{
 		CoordExtDegree:   1,
 		PointName:        "g1",
 		GLV:              true,
+		EdwardsMultiExp:  true,
 		CofactorCleaning: true,
 		CRange:           defaultCRange(),
 	},
@@ -56,6 +56,8 @@ type Point struct {
 	CoordExtRoot     int64 // value a, such that the field is Fp[X]/(Xⁿ - a)
 	PointName        string
 	GLV              bool     // scalar multiplication using GLV
+	GLVMultiExp      bool     // multiexp: split the scalars using the GLV endomorphism (requires GLV)
+	EdwardsMultiExp  bool     // multiexp: accumulate the buckets on the twisted Edwards form of the curve (requires a hand-written multiexp_edwards.go)
 	CofactorCleaning bool     // flag telling if the Cofactor cleaning is available
 	CRange           []int    // multiexp bucket method: generate inner methods (with const arrays) for each c
 	Projective       bool     // generate projective coordinates
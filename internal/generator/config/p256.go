@@ -0,0 +1,28 @@
+package config
+
+var P256 = Curve{
+	Name:         "p256",
+	CurvePackage: "p256",
+	EnumID:       "P256",
+	FrModulus:    "115792089210356248762697446949407573529996955224135760342422259061068512044369",
+	FpModulus:    "115792089210356248762697446949407573530086143415290314195533631308867097853951",
+	G1: Point{
+		CoordType:        "fp.Element",
+		CoordExtDegree:   1,
+		PointName:        "g1",
+		GLV:              false,
+		CofactorCleaning: false,
+		// r is 256 bits: with c = 16, the last window would need 17-bit digits
+		CRange: []int{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		A:      []string{"-3"},
+		B:      []string{"41058363725152142129326129780047268409114441015993725554835256314039467401291"},
+	},
+	// the curve has a ≠ 0, so the SSWU map applies directly (no isogeny)
+	HashE1: &HashSuiteSswu{
+		Z: []int{-10},
+	},
+}
+
+func init() {
+	addCurve(&P256)
+}
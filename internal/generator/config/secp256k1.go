@@ -11,8 +11,10 @@ var SECP256K1 = Curve{
 		CoordExtDegree:   1,
 		PointName:        "g1",
 		GLV:              true,
+		GLVMultiExp:      true,
 		CofactorCleaning: false,
-		CRange:           defaultCRange(),
+		// r is 256 bits: with c = 16, the last window would need 17-bit digits
+		CRange: []int{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
 	},
 	HashE1: &HashSuiteSvdw{
 		z:  []string{"1"},
@@ -2,6 +2,7 @@ package ecc
 
 import (
 	"fmt"
+	"math/big"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -13,10 +14,6 @@ import (
 )
 
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
-	if conf.Equal(config.SECP256K1) {
-		return nil
-	}
-
 	packageName := strings.ReplaceAll(conf.Name, "-", "")
 
 	entries := []bavard.Entry{
@@ -24,8 +21,25 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "multiexp_affine.go"), Templates: []string{"multiexp_affine.go.tmpl"}},
 		{File: filepath.Join(baseDir, "multiexp_jacobian.go"), Templates: []string{"multiexp_jacobian.go.tmpl"}},
 		{File: filepath.Join(baseDir, "multiexp_test.go"), Templates: []string{"tests/multiexp.go.tmpl"}},
-		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
-		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"tests/marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fixedbase.go"), Templates: []string{"fixedbase.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fixedbase_test.go"), Templates: []string{"tests/fixedbase.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed.go"), Templates: []string{"multiexp_precomputed.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed_test.go"), Templates: []string{"tests/multiexp_precomputed.go.tmpl"}},
+	}
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
+		// no G2 and no spare bits in fp: we use SEC1 encoding
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal_sec1.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"tests/marshal_sec1.go.tmpl"}},
+		)
+	} else {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"tests/marshal.go.tmpl"}},
+			// the streamed bases have a fixed size in raw encoding, which SEC1 doesn't have for the point at infinity
+			bavard.Entry{File: filepath.Join(baseDir, "multiexp_stream.go"), Templates: []string{"multiexp_stream.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "multiexp_stream_test.go"), Templates: []string{"tests/multiexp_stream.go.tmpl"}},
+		)
 	}
 	conf.Package = packageName
 	funcs := make(template.FuncMap)
@@ -145,6 +159,11 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 
 		funcs := make(template.FuncMap)
 		funcs["asElement"] = hashConf.Field.Base.WriteElement
+		// bits of a negative big.Int are those of its two's complement
+		funcs["abs"] = func(z big.Int) big.Int {
+			var res big.Int
+			return *res.Abs(&z)
+		}
 		bavardOpts := []func(*bavard.Bavard) error{bavard.Funcs(funcs)}
 
 		return bgen.GenerateWithOptions(hashConf, packageName, "./ecc/template", bavardOpts, entries...)
@@ -167,6 +186,11 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		return err
 	}
 
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
+		// no pairing, hence no G2
+		return nil
+	}
+
 	// G2
 	entries = []bavard.Entry{
 		{File: filepath.Join(baseDir, "g2.go"), Templates: []string{"point.go.tmpl"}},
@@ -8,7 +8,7 @@ import (
 )
 
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
-	if conf.Equal(config.SECP256K1) {
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
 		return nil
 	}
 
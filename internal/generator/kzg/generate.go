@@ -8,7 +8,7 @@ import (
 )
 
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
-	if conf.Equal(config.SECP256K1) {
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
 		return nil
 	}
 
@@ -19,6 +19,24 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "kzg.go"), Templates: []string{"kzg.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg_test.go"), Templates: []string{"kzg.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "lagrange.go"), Templates: []string{"lagrange.go.tmpl"}},
+		{File: filepath.Join(baseDir, "lagrange_test.go"), Templates: []string{"lagrange.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony.go"), Templates: []string{"ceremony.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony_test.go"), Templates: []string{"ceremony.test.go.tmpl"}},
+	}
+
+	// importers of the SRS of public ceremonies
+	if conf.Equal(config.BN254) || conf.Equal(config.BLS12_381) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ptau.go"), Templates: []string{"ptau.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ptau_test.go"), Templates: []string{"ptau.test.go.tmpl"}},
+		)
+	}
+	if conf.Equal(config.BLS12_381) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ethereum.go"), Templates: []string{"ethereum.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ethereum_test.go"), Templates: []string{"ethereum.test.go.tmpl"}},
+		)
 	}
 	return bgen.Generate(conf, conf.Package, "./kzg/template/", entries...)
 
@@ -13,6 +13,7 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
+	"github.com/consensys/gnark-crypto/internal/generator/ecdsa"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards/eddsa"
 	"github.com/consensys/gnark-crypto/internal/generator/fft"
@@ -24,6 +25,7 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/permutation"
 	"github.com/consensys/gnark-crypto/internal/generator/plookup"
 	"github.com/consensys/gnark-crypto/internal/generator/polynomial"
+	"github.com/consensys/gnark-crypto/internal/generator/shplonk"
 	"github.com/consensys/gnark-crypto/internal/generator/sumcheck"
 	"github.com/consensys/gnark-crypto/internal/generator/test_vector_utils"
 	"github.com/consensys/gnark-crypto/internal/generator/tower"
@@ -62,7 +64,7 @@ func main() {
 			assertNoError(generator.GenerateFF(conf.Fp, filepath.Join(curveDir, "fp")))
 
 			// generate tower of extension
-			assertNoError(tower.Generate(conf, filepath.Join(curveDir, "internal", "fptower"), bgen))
+			assertNoError(tower.Generate(conf, filepath.Join(curveDir, "fptower"), bgen))
 
 			// generate fft on fr
 			assertNoError(fft.Generate(conf, filepath.Join(curveDir, "fr", "fft"), bgen))
@@ -70,6 +72,9 @@ func main() {
 			// generate kzg on fr
 			assertNoError(kzg.Generate(conf, filepath.Join(curveDir, "fr", "kzg"), bgen))
 
+			// generate shplonk on fr
+			assertNoError(shplonk.Generate(conf, filepath.Join(curveDir, "fr", "shplonk"), bgen))
+
 			// generate pedersen on fr
 			assertNoError(pedersen.Generate(conf, filepath.Join(curveDir, "fr", "pedersen"), bgen))
 
@@ -100,7 +105,9 @@ func main() {
 			// generate pairing tests
 			assertNoError(pairing.Generate(conf, curveDir, bgen))
 
-			if conf.Equal(config.SECP256K1) {
+			if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
+				// generate ecdsa
+				assertNoError(ecdsa.Generate(conf, curveDir, bgen))
 				return // TODO @yelhousni
 			}
 			// generate sumcheck on fr
@@ -9,12 +9,14 @@ import (
 )
 
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
-	if conf.Equal(config.SECP256K1) {
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
 		return nil
 	}
 	packageName := strings.ReplaceAll(conf.Name, "-", "")
-	return bgen.Generate(conf, packageName, "./pairing/template", bavard.Entry{
-		File: filepath.Join(baseDir, "pairing_test.go"), Templates: []string{"tests/pairing.go.tmpl"},
-	})
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "pairing_batch.go"), Templates: []string{"batch.go.tmpl"}},
+		{File: filepath.Join(baseDir, "pairing_test.go"), Templates: []string{"tests/pairing.go.tmpl"}},
+	}
+	return bgen.Generate(conf, packageName, "./pairing/template", entries...)
 
 }
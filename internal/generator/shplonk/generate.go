@@ -0,0 +1,25 @@
+package shplonk
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
+		return nil
+	}
+
+	// shplonk multi-point batch openings of kzg commitments
+	conf.Package = "shplonk"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk.go"), Templates: []string{"shplonk.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk_test.go"), Templates: []string{"shplonk.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./shplonk/template/", entries...)
+
+}
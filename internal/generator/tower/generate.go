@@ -12,7 +12,16 @@ import (
 
 // Generate generates a tower 2->6->12 over fp
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
-	if conf.Equal(config.BW6_756) || conf.Equal(config.BW6_761) || conf.Equal(config.BW6_633) || conf.Equal(config.BLS24_315) || conf.Equal(config.BLS24_317) || conf.Equal(config.SECP256K1) {
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.P256) {
+		return nil
+	}
+
+	// the multi-exponentiation in GT is generated for all the towers
+	if err := generateMultiExp(conf, baseDir, bgen); err != nil {
+		return err
+	}
+
+	if conf.Equal(config.BW6_756) || conf.Equal(config.BW6_761) || conf.Equal(config.BW6_633) || conf.Equal(config.BLS24_315) || conf.Equal(config.BLS24_317) {
 		return nil
 	}
 
@@ -98,6 +107,28 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 
 }
 
+// generateMultiExp generates the multi-exponentiation of the GT elements of the tower
+func generateMultiExp(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	gt := gtConf{Name: conf.Name, GT: "E12", Degree: "¹²"}
+	switch {
+	case conf.Equal(config.BLS24_315) || conf.Equal(config.BLS24_317):
+		gt.GT, gt.Degree = "E24", "²⁴"
+	case conf.Equal(config.BW6_633) || conf.Equal(config.BW6_756) || conf.Equal(config.BW6_761):
+		gt.GT, gt.Degree = "E6", "⁶"
+	}
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "multiexp.go"), Templates: []string{"multiexp.go.tmpl"}},
+	}
+	return bgen.Generate(gt, "fptower", "./tower/template", entries...)
+}
+
+type gtConf struct {
+	Name   string
+	GT     string // type of the elements of GT in the tower
+	Degree string // embedding degree, as a superscript
+}
+
 type towerConf struct {
 	Curve           *config.Curve
 	RecursionDegree int